@@ -0,0 +1,7 @@
+package constants
+
+const (
+	ApprovalStatusOpen      = "open"
+	ApprovalStatusSubmitted = "submitted"
+	ApprovalStatusApproved  = "approved"
+)
@@ -0,0 +1,8 @@
+package constants
+
+const (
+	OutputFormatXlsx = "xlsx"
+	OutputFormatJson = "json"
+	OutputFormatCsv  = "csv"
+	OutputFormatHtml = "html"
+)
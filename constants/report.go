@@ -0,0 +1,70 @@
+package constants
+
+const (
+	GranularityDay   = "day"
+	GranularityWeek  = "week"
+	GranularityMonth = "month"
+)
+
+const (
+	LayoutSingle     = "single"
+	LayoutPerProject = "per_project"
+)
+
+const (
+	ReportByProject = "project"
+	ReportByUser    = "user"
+)
+
+const (
+	CostBasisLogged   = "logged"
+	CostBasisBillable = "billable"
+)
+
+const (
+	TeamPrefix = "team:" // prefix of Tempo team in user list
+)
+
+const (
+	RowRoleProject = "project"
+	RowRoleTeam    = "team"
+	RowRoleAccount = "account" // user heading projects of user in user report
+	RowRoleUser    = "user"
+	RowRoleGroup   = "group" // epic or work attribute value grouping issues of user
+	RowRoleIssue   = "issue"
+	RowRoleTotal   = "total"
+)
+
+const (
+	ColumnName     = "name"
+	ColumnPosition = "position"
+	ColumnTask     = "task"
+	ColumnRate     = "rate"
+	ColumnHours    = "hours"
+	ColumnCost     = "cost"
+	ColumnApproval = "approval"
+
+	ColumnLoggedHours   = "logged_hours"
+	ColumnBillableHours = "billable_hours"
+	ColumnBillableCost  = "billable_cost"
+
+	ColumnIssueFieldPrefix = "field:" // prefix of Jira issue field columns
+)
+
+const (
+	IssuesOrderKey         = "key"
+	IssuesOrderHours       = "hours"
+	IssuesOrderFirstLogged = "first_logged"
+	IssuesOrderSummary     = "summary"
+)
+
+const (
+	UsersOrderName     = "name"
+	UsersOrderPosition = "position"
+	UsersOrderCost     = "cost"
+)
+
+const (
+	TeamsSourceProjectConfig = "project_config"
+	TeamsSourceTempo         = "tempo"
+)
@@ -0,0 +1,14 @@
+package constants
+
+const (
+	RoundingModeNone    = "none"
+	RoundingModeNearest = "nearest"
+	RoundingModeUp      = "up"
+)
+
+const (
+	RoundingScopeWorklog = "worklog"
+	RoundingScopeDay     = "day"
+	RoundingScopeIssue   = "issue"
+	RoundingScopeTotal   = "total"
+)
@@ -3,6 +3,8 @@ package main
 import (
 	"log"
 	"os"
+	"tempo-worklog/constants"
+	"tempo-worklog/models"
 	"tempo-worklog/services"
 )
 
@@ -28,28 +30,126 @@ func main() {
 	}
 
 	// get data
+	var issueFields []string
+	for _, field := range appConfig.ReportLayout.IssueFields {
+		issueFields = append(issueFields, field.Name)
+	}
+
 	worklogService := services.NewWorklogService(
 		appConfig.Jira.Url,
 		appConfig.Jira.UserEmail,
 		appConfig.Jira.UserToken,
 		appConfig.Jira.TempoToken,
+		appConfig.Report.Teams && appConfig.Report.TeamsSource == constants.TeamsSourceTempo,
+		appConfig.Report.CostBasis,
+		issueFields,
+		appConfig.Report.GroupBy,
+		appConfig.Attributes,
+		appConfig.Projects,
 		services.NewProjectConfigService(appConfig.Files.ProjectConfigFile))
 
-	worklog, err := worklogService.GetWorklog(inputArgs.Projects, inputArgs.DateFrom, inputArgs.DateTo)
+	var worklog *models.Worklog
+	switch {
+	case len(inputArgs.ImportFile) > 0:
+		importService := services.NewImportService(
+			appConfig.Import,
+			worklogService,
+			services.NewProjectConfigService(appConfig.Files.ProjectConfigFile))
+
+		// offline import has no access to Jira and Tempo
+		err = importService.CheckConfig(appConfig, inputArgs.Jql)
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+
+		keys := inputArgs.Projects
+		if inputArgs.By == constants.ReportByUser {
+			keys = inputArgs.Users
+		}
+		worklog, err = importService.GetWorklog(inputArgs.ImportFile, inputArgs.By, keys, inputArgs.DateFrom, inputArgs.DateTo)
+	case inputArgs.By == constants.ReportByUser:
+		worklog, err = worklogService.GetUserWorklog(inputArgs.Users, inputArgs.DateFrom, inputArgs.DateTo, inputArgs.Jql)
+	default:
+		worklog, err = worklogService.GetWorklog(inputArgs.Projects, inputArgs.DateFrom, inputArgs.DateTo, inputArgs.Jql)
+	}
 	if err != nil {
 		log.Fatal(err)
 		return
 	}
 
-	// save data
-	excelService := services.NewExcelService(appConfig.Files.ReportFile)
+	// timesheet approvals
+	if appConfig.Report.Approvals || appConfig.Report.ApprovedOnly {
+		accountIdToApprovals, err := worklogService.GetApprovals(worklog, inputArgs.DateFrom, inputArgs.DateTo)
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
 
-	err = excelService.Save(worklog, inputArgs.DateFrom, inputArgs.DateTo)
+		approvalService := services.NewApprovalService(appConfig.Report.ApprovedOnly)
+		approvalService.Apply(worklog, accountIdToApprovals)
+	}
+
+	// plans
+	if appConfig.Report.Plans {
+		err = worklogService.GetPlans(worklog, inputArgs.Users, inputArgs.DateFrom, inputArgs.DateTo)
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+	}
+
+	// rounding
+	roundingService := services.NewRoundingService(appConfig.Rounding, appConfig.Projects)
+
+	err = roundingService.Apply(worklog)
 	if err != nil {
 		log.Fatal(err)
 		return
 	}
 
+	// ordering
+	sortService := services.NewSortService(appConfig.Report.IssuesOrder, appConfig.Report.UsersOrder)
+
+	err = sortService.Sort(worklog)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	// save data
+	pricingService := services.NewPricingService(appConfig.Report.Granularity)
+	exportService := services.NewExportService(appConfig.Files.ReportFile, appConfig.ReportLayout.Theme, pricingService)
+
+	for _, format := range inputArgs.OutputFormats {
+		filePath := exportService.GetFilePath(format)
+
+		switch format {
+		case constants.OutputFormatXlsx:
+			filePath = appConfig.Files.ReportFile
+
+			excelService := services.NewExcelService(
+				appConfig.Files.ReportFile,
+				appConfig.Files.ReportTemplateFile,
+				appConfig.Report,
+				appConfig.ReportLayout,
+				pricingService)
+
+			err = excelService.Save(worklog, inputArgs.DateFrom, inputArgs.DateTo)
+		case constants.OutputFormatJson:
+			err = exportService.SaveJson(worklog, inputArgs.DateFrom, inputArgs.DateTo)
+		case constants.OutputFormatCsv:
+			err = exportService.SaveCsv(worklog, inputArgs.DateFrom, inputArgs.DateTo)
+		case constants.OutputFormatHtml:
+			err = exportService.SaveHtml(worklog, inputArgs.DateFrom, inputArgs.DateTo)
+		}
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+
+		log.Println("See", filePath)
+	}
+
 	log.Println("Report creating finished successfully")
-	log.Println("See", appConfig.Files.ReportFile)
 }
@@ -1,8 +1,14 @@
 package models
 
 type AppConfig struct {
-	Jira  JiraAppConfig  `mapstructure:"jira"`
-	Files FilesAppConfig `mapstructure:"files"`
+	Jira         JiraAppConfig               `mapstructure:"jira"`
+	Files        FilesAppConfig              `mapstructure:"files"`
+	Report       ReportAppConfig             `mapstructure:"report"`
+	ReportLayout ReportLayoutAppConfig       `mapstructure:"report_layout"`
+	Rounding     RoundingAppConfig           `mapstructure:"rounding"`
+	Projects     map[string]ProjectAppConfig `mapstructure:"projects"` // keys are lower case project keys
+	Attributes   AttributesAppConfig         `mapstructure:"attributes"`
+	Import       ImportAppConfig             `mapstructure:"import"`
 }
 
 type JiraAppConfig struct {
@@ -13,6 +19,93 @@ type JiraAppConfig struct {
 }
 
 type FilesAppConfig struct {
-	ProjectConfigFile string `mapstructure:"project_config"`
-	ReportFile        string `mapstructure:"report"`
+	ProjectConfigFile  string `mapstructure:"project_config"`
+	ReportFile         string `mapstructure:"report"`
+	ReportTemplateFile string `mapstructure:"report_template"`
+}
+
+type ReportAppConfig struct {
+	Granularity   string `mapstructure:"granularity"`
+	Layout        string `mapstructure:"layout"`
+	Charts        bool   `mapstructure:"charts"`
+	DataSheet     bool   `mapstructure:"data_sheet"`
+	WorklogsSheet bool   `mapstructure:"worklogs_sheet"`
+	PivotTable    bool   `mapstructure:"pivot_table"`
+	ValuesOnly    bool   `mapstructure:"values_only"`
+	IssuesOrder   string `mapstructure:"issues_order"`
+	UsersOrder    string `mapstructure:"users_order"`
+	Teams         bool   `mapstructure:"teams"`
+	TeamsSource   string `mapstructure:"teams_source"`
+	Epics         bool   `mapstructure:"epics"`
+	CostBasis     string `mapstructure:"cost_basis"`
+	GroupBy       string `mapstructure:"group_by_attribute"`
+	Approvals     bool   `mapstructure:"approvals"`
+	ApprovedOnly  bool   `mapstructure:"approved_only"`
+	Plans         bool   `mapstructure:"plans"`
+	PlanThreshold int    `mapstructure:"plan_threshold"` // percentage of actual hours over plan to highlight
+}
+
+type ReportLayoutAppConfig struct {
+	Theme       ReportThemeAppConfig    `mapstructure:"theme"`
+	Columns     []ReportColumnAppConfig `mapstructure:"columns"`
+	IssueFields []ReportColumnAppConfig `mapstructure:"issue_fields"`
+}
+
+type ReportThemeAppConfig struct {
+	FontFamily      string  `mapstructure:"font_family"`
+	FontSize        float64 `mapstructure:"font_size"`
+	FontColor       string  `mapstructure:"font_color"`
+	HeaderFontSize  float64 `mapstructure:"header_font_size"`
+	HeaderFontColor string  `mapstructure:"header_font_color"`
+	HeaderColor     string  `mapstructure:"header_color"`
+	ProjectColor    string  `mapstructure:"project_color"`
+	TeamColor       string  `mapstructure:"team_color"`
+	UserColor       string  `mapstructure:"user_color"`
+	EpicColor       string  `mapstructure:"epic_color"`
+	TotalColor      string  `mapstructure:"total_color"`
+	WeekendColor    string  `mapstructure:"weekend_color"`
+}
+
+type ReportColumnAppConfig struct {
+	Name   string  `mapstructure:"name"`
+	Header string  `mapstructure:"header"`
+	Width  float64 `mapstructure:"width"`
+}
+
+type RoundingAppConfig struct {
+	Mode    string `mapstructure:"mode"`
+	Minutes int    `mapstructure:"minutes"`
+	Scope   string `mapstructure:"scope"`
+}
+
+type ProjectAppConfig struct {
+	Rounding RoundingAppConfig `mapstructure:"rounding"`
+	Jql      string            `mapstructure:"jql"`
+}
+
+type AttributesAppConfig struct {
+	Filter        map[string][]string `mapstructure:"filter"` // attribute key to allowed values
+	WorkTypeKey   string              `mapstructure:"work_type_key"`
+	WorkTypeRates map[string]int      `mapstructure:"work_type_rates"`
+}
+
+// ImportAppConfig describes CSV or XLSX export of Tempo or Jira worklog read instead of the API.
+type ImportAppConfig struct {
+	Sheet      string                 `mapstructure:"sheet"` // sheet of XLSX file, the first one if empty
+	Delimiter  string                 `mapstructure:"delimiter"`
+	DateFormat string                 `mapstructure:"date_format"` // Go layout of date column, e.g. 2006-01-02 15:04
+	Columns    ImportColumnsAppConfig `mapstructure:"columns"`
+}
+
+// ImportColumnsAppConfig maps worklog values to header names of the file, empty names are not read.
+type ImportColumnsAppConfig struct {
+	Issue         string `mapstructure:"issue"`
+	Summary       string `mapstructure:"summary"`
+	User          string `mapstructure:"user"`
+	AccountId     string `mapstructure:"account_id"`
+	Date          string `mapstructure:"date"`
+	StartTime     string `mapstructure:"start_time"`
+	Hours         string `mapstructure:"hours"`
+	BillableHours string `mapstructure:"billable_hours"`
+	Description   string `mapstructure:"description"`
 }
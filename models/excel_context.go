@@ -1,7 +1,45 @@
 package models
 
 type ExcelContext struct {
+	Sheet                string
+	By                   string // project or user, see worklog
+	Columns              []ReportColumnAppConfig
+	ColumnToIndex        map[string]int // column name to its index in the sheet
+	FirstColumnIndex     int
 	FirstDateColumnIndex int
 	ColsCount            int
 	LastRowIndex         int
+	Periods              []ReportPeriod
+	DateToPeriod         map[string]int // date to index of report period
+	Styles               ExcelStyles
+	Rows                 []ExcelRow
+	TotalHours           float64
+	TotalCost            float64
+}
+
+// ExcelRow describes the role of the sheet row, subtotal and total formulas are built from it.
+type ExcelRow struct {
+	Index          int
+	Role           string
+	Label          string // label of group row
+	ProjectKey     string
+	Rounding       RoundingAppConfig
+	Team           string
+	User           User
+	Rate           int // rate of user or rate override of work type of issue
+	Issue          Issue
+	Children       []int // indexes of rows summed up by the row
+	ColumnToHours  map[int]float64
+	WeekendPeriods map[int]bool // indexes of report periods with hours logged on weekends
+	Hours          float64
+	Cost           float64
+	LoggedHours    float64
+	BillableHours  float64
+	BillableCost   float64
+}
+
+// ExcelIssueGroup is the group of issue rows of user, e.g. epic.
+type ExcelIssueGroup struct {
+	Label  string
+	Issues []Issue
 }
@@ -0,0 +1,26 @@
+package models
+
+type ExcelStyles struct {
+	Header        int
+	HeaderPeriod  int
+	HeaderWeekend int
+	Project       int
+	Team          int
+	TeamCost      int
+	TeamPeriod    int
+	User          int
+	UserRate      int
+	UserHours     int
+	UserCost      int
+	UserPeriod    int
+	Group         int
+	GroupCost     int
+	GroupPeriod   int
+	IssueTask     int
+	IssueRate     int
+	IssueCost     int
+	IssuePeriod   int
+	Weekend       int
+	Total         int
+	TotalCost     int
+}
@@ -0,0 +1,15 @@
+package models
+
+// ExcelAnchor is the top left cell of the report part placed into the sheet.
+type ExcelAnchor struct {
+	Sheet       string
+	ColumnIndex int
+	RowIndex    int
+}
+
+// ExcelTemplateRow is the row of template sheet rewritten to the report as is.
+type ExcelTemplateRow struct {
+	Height     float64
+	Cells      []interface{}
+	MergeCells [][]string // top left and bottom right cells of merged ranges starting in the row
+}
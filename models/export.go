@@ -0,0 +1,66 @@
+package models
+
+// ExportReport is priced worklog written to machine-readable outputs, hours and costs are the same as in Excel report.
+type ExportReport struct {
+	DateFrom string          `json:"dateFrom"`
+	DateTo   string          `json:"dateTo"`
+	By       string          `json:"by"`
+	Hours    float64         `json:"hours"`
+	Cost     float64         `json:"cost"`
+	Projects []ExportProject `json:"projects"`
+}
+
+type ExportProject struct {
+	Key   string       `json:"key"`
+	Hours float64      `json:"hours"`
+	Cost  float64      `json:"cost"`
+	Users []ExportUser `json:"users"`
+}
+
+type ExportUser struct {
+	AccountId string        `json:"accountId"`
+	Name      string        `json:"name"`
+	Position  string        `json:"position"`
+	Team      string        `json:"team,omitempty"`
+	Approval  string        `json:"approval,omitempty"`
+	Rate      int           `json:"rate"`
+	Hours     float64       `json:"hours"`
+	Cost      float64       `json:"cost"`
+	Issues    []ExportIssue `json:"issues"`
+}
+
+type ExportIssue struct {
+	Key           string            `json:"key"`
+	Summary       string            `json:"summary"`
+	Type          string            `json:"type,omitempty"`
+	EpicKey       string            `json:"epicKey,omitempty"`
+	EpicSummary   string            `json:"epicSummary,omitempty"`
+	Attribute     string            `json:"attribute,omitempty"`
+	WorkType      string            `json:"workType,omitempty"`
+	Unapproved    bool              `json:"unapproved,omitempty"`
+	Fields        map[string]string `json:"fields,omitempty"`
+	Rate          int               `json:"rate"`
+	Hours         float64           `json:"hours"`
+	Cost          float64           `json:"cost"`
+	LoggedHours   float64           `json:"loggedHours"`
+	BillableHours float64           `json:"billableHours"`
+	BillableCost  float64           `json:"billableCost"`
+	Days          []ExportDay       `json:"days"`
+}
+
+type ExportDay struct {
+	Date           string        `json:"date"`
+	Hours          float64       `json:"hours"`
+	UnroundedHours float64       `json:"unroundedHours"`
+	Cost           float64       `json:"cost"`
+	LoggedHours    float64       `json:"loggedHours"`
+	BillableHours  float64       `json:"billableHours"`
+	Entries        []ExportEntry `json:"entries,omitempty"`
+}
+
+// ExportEntry is single worklog of the day.
+type ExportEntry struct {
+	StartTime   string  `json:"startTime,omitempty"`
+	Hours       float64 `json:"hours"`
+	Description string  `json:"description,omitempty"`
+}
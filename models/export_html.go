@@ -0,0 +1,60 @@
+package models
+
+// HtmlReport is view of priced worklog rendered to HTML report, values are formatted for display.
+type HtmlReport struct {
+	Title     string
+	Theme     ReportThemeAppConfig
+	Days      []HtmlDay
+	Hours     string
+	Cost      string
+	Projects  []HtmlProject
+	HeatColor string // red, green and blue of the theme header color heating day cells, e.g. 36, 135, 188
+}
+
+type HtmlDay struct {
+	Label     string // day of month
+	Weekday   string
+	IsWeekend bool
+}
+
+type HtmlProject struct {
+	Key   string
+	Hours string
+	Cost  string
+	Users []HtmlUser
+}
+
+type HtmlUser struct {
+	Name      string
+	Position  string
+	Team      string
+	Approval  string
+	Rate      int
+	Hours     string
+	Cost      string
+	Issues    []HtmlIssue
+	DayTotals []HtmlCell
+}
+
+type HtmlIssue struct {
+	Task    string
+	Rate    int
+	Hours   string
+	Cost    string
+	Cells   []HtmlCell
+	Entries []HtmlEntry
+}
+
+// HtmlCell is hours of the day coloured by heat, the share of the day in the busiest day of the report.
+type HtmlCell struct {
+	Hours     string
+	Heat      float64
+	IsWeekend bool
+}
+
+type HtmlEntry struct {
+	Date        string
+	StartTime   string
+	Hours       string
+	Description string
+}
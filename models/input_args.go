@@ -1,8 +1,13 @@
 package models
 
 type InputArgs struct {
-	ConfigFile string
-	Projects   []string
-	DateFrom   string
-	DateTo     string
+	ConfigFile    string
+	By            string
+	Projects      []string
+	Users         []string // account ids or Tempo teams of user report
+	DateFrom      string
+	DateTo        string
+	Jql           string
+	ImportFile    string   // CSV or XLSX export read instead of Tempo API
+	OutputFormats []string // xlsx, json, csv or html
 }
@@ -0,0 +1,24 @@
+package models
+
+// UserPrice is worklog of user in the project priced the way the detail sheet shows it.
+type UserPrice struct {
+	PeriodToHours  map[int]float64 // index of report period to hours
+	WeekendPeriods map[int]bool    // indexes of report periods with hours logged on weekends
+	Hours          float64         // hours of periods or of rounded issues, rounded by total scope
+	Cost           float64
+	LoggedHours    float64
+	BillableHours  float64
+	BillableCost   float64
+	Issues         []IssuePrice // prices of issues of user in the same order
+}
+
+type IssuePrice struct {
+	Rate           int             // rate of user or rate override of work type of issue
+	PeriodToHours  map[int]float64 // index of report period to hours
+	WeekendPeriods map[int]bool    // indexes of report periods with hours logged on weekends
+	Hours          float64         // hours of periods rounded by issue scope
+	Cost           float64
+	LoggedHours    float64
+	BillableHours  float64
+	BillableCost   float64
+}
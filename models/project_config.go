@@ -11,4 +11,5 @@ type ProjectConfig struct {
 type UserConfig struct {
 	Position string
 	Rate     int
+	Team     string
 }
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+type ReportPeriod struct {
+	Label     string
+	DateFrom  time.Time
+	DateTo    time.Time
+	IsWeekend bool
+}
@@ -3,12 +3,15 @@ package models
 // worklog -> projects -> users -> issues -> efforts
 
 type Worklog struct {
+	By       string // project or user, what the worklog is fetched and reported by
 	Projects []Project
 }
 
 type Project struct {
-	Key   string
-	Users []User
+	Key      string
+	Rounding RoundingAppConfig
+	Users    []User
+	Plans    []Plan // Tempo plans of users planned for the project, empty if not fetched
 }
 
 type User struct {
@@ -16,16 +19,45 @@ type User struct {
 	DisplayName string
 	Position    string
 	Rate        int
+	Team        string
+	Approval    string // least approved timesheet status within the report dates, empty if not fetched
 	Issues      []Issue
 }
 
+// Plan is planned time of user in the project within the report dates.
+type Plan struct {
+	AccountId      string
+	DisplayName    string
+	PlannedSeconds int
+}
+
 type Issue struct {
-	Key     string
-	Summary string
-	Efforts []Effort
+	Key         string
+	Summary     string
+	Type        string
+	EpicKey     string
+	EpicSummary string
+	Fields      map[string]string // Jira field id to its text
+	Attribute   string            // value of work attribute the report is grouped by
+	WorkType    string            // work type with rate override
+	Rate        int               // rate override of work type, 0 means rate of user
+	Unapproved  bool              // time of unapproved days priced at zero rate if only approved time is billed
+	Efforts     []Effort
 }
 
 type Effort struct {
 	Date             string
 	TimeSpentSeconds int
+	UnroundedSeconds int   // time spent before rounding, kept for audit
+	WorklogsSeconds  []int // time spent of each worklog of the day
+	LoggedSeconds    int   // logged time of the day as is
+	BillableSeconds  int   // billable time of the day as is
+	Entries          []WorklogEntry
+}
+
+// WorklogEntry is single worklog of the day as it is logged in Tempo.
+type WorklogEntry struct {
+	StartTime        string
+	TimeSpentSeconds int
+	Description      string
 }
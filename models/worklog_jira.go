@@ -8,10 +8,50 @@ type JiraSearchIssueResponse struct {
 }
 
 type JiraSearchIssue struct {
+	Id     string                `json:"id"`
 	Key    string                `json:"key"`
 	Fields JiraSearchIssueFields `json:"fields"`
 }
 
 type JiraSearchIssueFields struct {
-	Summary string `json:"summary"`
+	Summary   string                 `json:"summary"`
+	IssueType JiraIssueType          `json:"issuetype"`
+	Parent    *JiraParentIssue       `json:"parent"`
+	Extra     map[string]interface{} `json:"-"` // all fields as is, used for report issue fields
+}
+
+// JiraSearchIssueRawResponse keeps issue fields as is, since types of custom fields are not known in advance.
+type JiraSearchIssueRawResponse struct {
+	Issues []JiraSearchIssueRaw `json:"issues"`
+}
+
+type JiraSearchIssueRaw struct {
+	Key    string                 `json:"key"`
+	Fields map[string]interface{} `json:"fields"`
+}
+
+type JiraIssueType struct {
+	Name           string `json:"name"`
+	Subtask        bool   `json:"subtask"`
+	HierarchyLevel int    `json:"hierarchyLevel"`
+}
+
+type JiraParentIssue struct {
+	Key    string                `json:"key"`
+	Fields JiraParentIssueFields `json:"fields"`
+}
+
+type JiraParentIssueFields struct {
+	Summary   string        `json:"summary"`
+	IssueType JiraIssueType `json:"issuetype"`
+}
+
+type JiraProject struct {
+	Id  string `json:"id"`
+	Key string `json:"key"`
+}
+
+type JiraUser struct {
+	AccountId   string `json:"accountId"`
+	DisplayName string `json:"displayName"`
 }
@@ -1,5 +1,7 @@
 package models
 
+import "encoding/json"
+
 type TempoResponse struct {
 	Metadata TempoMetadata `json:"metadata"`
 	Results  []TempoResult `json:"results"`
@@ -12,10 +14,24 @@ type TempoMetadata struct {
 }
 
 type TempoResult struct {
-	Author           TempoAuthor `json:"author"`
-	Issue            TempoIssue  `json:"issue"`
-	StartDate        string      `json:"startDate"`
-	TimeSpentSeconds int         `json:"timeSpentSeconds"`
+	Author           TempoAuthor     `json:"author"`
+	Issue            TempoIssue      `json:"issue"`
+	StartDate        string          `json:"startDate"`
+	StartTime        string          `json:"startTime"`
+	Description      string          `json:"description"`
+	TimeSpentSeconds int             `json:"timeSpentSeconds"`
+	BillableSeconds  int             `json:"billableSeconds"`
+	Attributes       TempoAttributes `json:"attributes"`
+}
+
+type TempoAttributes struct {
+	Values []TempoAttribute `json:"values"`
+}
+
+// TempoAttribute is work attribute of worklog, e.g. _Account_ or _WorkType_.
+type TempoAttribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
 }
 
 type TempoAuthor struct {
@@ -26,3 +42,59 @@ type TempoAuthor struct {
 type TempoIssue struct {
 	Key string `json:"key"`
 }
+
+type TempoTeamsResponse struct {
+	Results []TempoTeam `json:"results"`
+}
+
+type TempoTeam struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type TempoTeamMembersResponse struct {
+	Results []TempoTeamMember `json:"results"`
+}
+
+type TempoTeamMember struct {
+	Member TempoAuthor `json:"member"`
+}
+
+// TempoApproval is timesheet approval of user for a period, e.g. a week or a month.
+type TempoApproval struct {
+	Period TempoApprovalPeriod `json:"period"`
+	Status TempoApprovalStatus `json:"status"`
+}
+
+type TempoApprovalPeriod struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type TempoApprovalStatus struct {
+	Key string `json:"key"` // OPEN, IN_REVIEW or APPROVED
+}
+
+type TempoPlansResponse struct {
+	Metadata TempoMetadata `json:"metadata"`
+	Results  []TempoPlan   `json:"results"`
+}
+
+// TempoPlan is Tempo Planner allocation of user to issue or project.
+type TempoPlan struct {
+	StartDate                  string        `json:"startDate"`
+	EndDate                    string        `json:"endDate"`
+	TotalPlannedSecondsInScope int           `json:"totalPlannedSecondsInScope"` // planned time within requested dates
+	PlanItem                   TempoPlanItem `json:"planItem"`
+	Assignee                   TempoAssignee `json:"assignee"`
+}
+
+type TempoAssignee struct {
+	AccountId string `json:"accountId"`
+	Type      string `json:"type"` // USER or GENERIC
+}
+
+type TempoPlanItem struct {
+	Id   json.Number `json:"id"`   // Jira issue or project id
+	Type string      `json:"type"` // ISSUE or PROJECT
+}
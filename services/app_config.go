@@ -1,8 +1,11 @@
 package services
 
 import (
+	"errors"
 	"github.com/spf13/viper"
 	"log"
+	"strings"
+	"tempo-worklog/constants"
 	"tempo-worklog/models"
 	"tempo-worklog/utils"
 )
@@ -18,6 +21,44 @@ func NewAppConfigService(filePath string) *AppConfigService {
 func (s *AppConfigService) Get() (*models.AppConfig, error) {
 	viper.SetConfigFile(s.filePath)
 	viper.SetConfigType("yaml")
+	viper.SetDefault("report.granularity", constants.GranularityDay)
+	viper.SetDefault("report.layout", constants.LayoutSingle)
+	viper.SetDefault("report.issues_order", constants.IssuesOrderKey)
+	viper.SetDefault("report.users_order", constants.UsersOrderName)
+	viper.SetDefault("report.teams_source", constants.TeamsSourceProjectConfig)
+	viper.SetDefault("report.cost_basis", constants.CostBasisLogged)
+	viper.SetDefault("report.plan_threshold", 10)
+	viper.SetDefault("attributes.work_type_key", "_WorkType_")
+	viper.SetDefault("import.date_format", "2006-01-02 15:04")
+	viper.SetDefault("import.columns.issue", "Issue Key")
+	viper.SetDefault("import.columns.summary", "Issue summary")
+	viper.SetDefault("import.columns.user", "Full name")
+	viper.SetDefault("import.columns.date", "Work date")
+	viper.SetDefault("import.columns.hours", "Hours")
+	viper.SetDefault("import.columns.billable_hours", "Billed Hours")
+	viper.SetDefault("import.columns.description", "Work Description")
+	viper.SetDefault("rounding.mode", constants.RoundingModeNone)
+	viper.SetDefault("rounding.scope", constants.RoundingScopeDay)
+	viper.SetDefault("report_layout.theme.font_family", "Calibri")
+	viper.SetDefault("report_layout.theme.font_size", 12)
+	viper.SetDefault("report_layout.theme.font_color", "#000000")
+	viper.SetDefault("report_layout.theme.header_font_size", 13)
+	viper.SetDefault("report_layout.theme.header_font_color", "#ffffff")
+	viper.SetDefault("report_layout.theme.header_color", "#2487bc")
+	viper.SetDefault("report_layout.theme.project_color", "#bee0f2")
+	viper.SetDefault("report_layout.theme.team_color", "#c9dfea")
+	viper.SetDefault("report_layout.theme.user_color", "#d3e2ea")
+	viper.SetDefault("report_layout.theme.epic_color", "#eef5f9")
+	viper.SetDefault("report_layout.theme.total_color", "#53aede")
+	viper.SetDefault("report_layout.theme.weekend_color", "#FEC7CE")
+	viper.SetDefault("report_layout.columns", []map[string]interface{}{
+		{"name": constants.ColumnName},
+		{"name": constants.ColumnPosition},
+		{"name": constants.ColumnTask},
+		{"name": constants.ColumnRate},
+		{"name": constants.ColumnHours},
+		{"name": constants.ColumnCost},
+	})
 
 	err := viper.ReadInConfig()
 	if err != nil {
@@ -31,7 +72,103 @@ func (s *AppConfigService) Get() (*models.AppConfig, error) {
 		return nil, err
 	}
 
+	err = s.validate(&appConfig)
+	if err != nil {
+		return nil, err
+	}
+
 	log.Println("Parsed", s.filePath, utils.ToPrettyString("config", appConfig))
 
 	return &appConfig, nil
 }
+
+// validate checks enumerated options, so a typo fails on start instead of in the middle of the report.
+func (s *AppConfigService) validate(appConfig *models.AppConfig) error {
+	report := appConfig.Report
+
+	switch report.Granularity {
+	case constants.GranularityDay, constants.GranularityWeek, constants.GranularityMonth:
+	default:
+		return errors.New("unknown report granularity: " + report.Granularity)
+	}
+
+	switch report.Layout {
+	case constants.LayoutSingle, constants.LayoutPerProject:
+	default:
+		return errors.New("unknown report layout: " + report.Layout)
+	}
+
+	switch report.CostBasis {
+	case constants.CostBasisLogged, constants.CostBasisBillable:
+	default:
+		return errors.New("unknown cost basis: " + report.CostBasis)
+	}
+
+	switch report.IssuesOrder {
+	case constants.IssuesOrderKey, constants.IssuesOrderHours, constants.IssuesOrderFirstLogged, constants.IssuesOrderSummary:
+	default:
+		return errors.New("unknown issues order: " + report.IssuesOrder)
+	}
+
+	switch report.UsersOrder {
+	case constants.UsersOrderName, constants.UsersOrderPosition, constants.UsersOrderCost:
+	default:
+		return errors.New("unknown users order: " + report.UsersOrder)
+	}
+
+	switch report.TeamsSource {
+	case constants.TeamsSourceProjectConfig, constants.TeamsSourceTempo:
+	default:
+		return errors.New("unknown teams source: " + report.TeamsSource)
+	}
+
+	err := s.validateRounding(appConfig.Rounding, "")
+	if err != nil {
+		return err
+	}
+
+	for projectKey, projectConfig := range appConfig.Projects {
+		if len(projectConfig.Rounding.Mode) == 0 {
+			continue // rounding of the project is not overridden
+		}
+
+		err = s.validateRounding(projectConfig.Rounding, projectKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, column := range appConfig.ReportLayout.Columns {
+		if _, ok := ReportColumnDefaults[strings.ToLower(column.Name)]; !ok {
+			return errors.New("unknown report column: " + column.Name)
+		}
+	}
+
+	return nil
+}
+
+// validateRounding checks rounding mode and scope, empty scope of the project is taken from the default one.
+func (s *AppConfigService) validateRounding(rounding models.RoundingAppConfig, projectKey string) error {
+	suffix := ""
+	if len(projectKey) > 0 {
+		suffix = " of project " + strings.ToUpper(projectKey)
+	}
+
+	switch rounding.Mode {
+	case constants.RoundingModeNone, constants.RoundingModeNearest, constants.RoundingModeUp:
+	default:
+		return errors.New("unknown rounding mode" + suffix + ": " + rounding.Mode)
+	}
+
+	if len(projectKey) > 0 && len(rounding.Scope) == 0 {
+		return nil
+	}
+
+	switch rounding.Scope {
+	case constants.RoundingScopeWorklog, constants.RoundingScopeDay, constants.RoundingScopeIssue, constants.RoundingScopeTotal:
+	default:
+		return errors.New("unknown rounding scope" + suffix + ": " + rounding.Scope)
+	}
+
+	return nil
+}
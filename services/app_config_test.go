@@ -0,0 +1,54 @@
+package services
+
+import (
+	"github.com/spf13/viper"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAppConfigGet(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{name: "defaults", config: "report:\n  charts: true\n"},
+		{name: "valid options", config: "report:\n  granularity: week\n  layout: per_project\n  cost_basis: billable\n  issues_order: hours\n  users_order: cost\n  teams_source: tempo\n"},
+		{name: "granularity", config: "report:\n  granularity: year\n", wantErr: "unknown report granularity: year"},
+		{name: "layout", config: "report:\n  layout: single_sheet\n", wantErr: "unknown report layout: single_sheet"},
+		{name: "cost basis", config: "report:\n  cost_basis: billed\n", wantErr: "unknown cost basis: billed"},
+		{name: "issues order", config: "report:\n  issues_order: date\n", wantErr: "unknown issues order: date"},
+		{name: "users order", config: "report:\n  users_order: team\n", wantErr: "unknown users order: team"},
+		{name: "teams source", config: "report:\n  teams_source: jira\n", wantErr: "unknown teams source: jira"},
+		{name: "rounding mode", config: "rounding:\n  mode: down\n", wantErr: "unknown rounding mode: down"},
+		{name: "rounding scope", config: "rounding:\n  mode: up\n  scope: week\n", wantErr: "unknown rounding scope: week"},
+		{name: "project rounding mode", config: "projects:\n  prj:\n    rounding:\n      mode: down\n", wantErr: "unknown rounding mode of project PRJ: down"},
+		{name: "project rounding without scope", config: "projects:\n  prj:\n    rounding:\n      mode: up\n      minutes: 15\n"},
+		{name: "column", config: "report_layout:\n  columns:\n    - name: name\n    - name: hourz\n", wantErr: "unknown report column: hourz"},
+		{name: "column in upper case", config: "report_layout:\n  columns:\n    - name: Name\n    - name: Hours\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			viper.Reset()
+
+			filePath := filepath.Join(t.TempDir(), "config.yaml")
+			err := os.WriteFile(filePath, []byte(test.config), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = NewAppConfigService(filePath).Get()
+			if len(test.wantErr) > 0 {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("Get() error = %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
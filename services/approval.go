@@ -0,0 +1,122 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"tempo-worklog/constants"
+	"tempo-worklog/models"
+)
+
+var approvalStatusOrder = []string{constants.ApprovalStatusOpen, constants.ApprovalStatusSubmitted, constants.ApprovalStatusApproved}
+
+type ApprovalService struct {
+	approvedOnly bool
+}
+
+func NewApprovalService(approvedOnly bool) *ApprovalService {
+	return &ApprovalService{approvedOnly: approvedOnly}
+}
+
+// Apply sets timesheet approval status of users, if only approved time is billed, efforts of unapproved days
+// are split off to issue parts with zero cost, so their hours are kept. Days outside of any approval period are open.
+func (s *ApprovalService) Apply(worklog *models.Worklog, accountIdToApprovals map[string][]models.TempoApproval) {
+	for i := range worklog.Projects {
+		project := &worklog.Projects[i]
+
+		for j := range project.Users {
+			user := &project.Users[j]
+			approvals := accountIdToApprovals[user.AccountId]
+			user.Approval = s.getUserStatus(approvals)
+
+			unapprovedSeconds := 0
+
+			var issues []models.Issue
+			for _, issue := range user.Issues {
+				var efforts, unapprovedEfforts []models.Effort
+				for _, effort := range issue.Efforts {
+					if s.getStatus(approvals, effort.Date) == constants.ApprovalStatusApproved {
+						efforts = append(efforts, effort)
+						continue
+					}
+
+					unapprovedSeconds += effort.TimeSpentSeconds
+					unapprovedEfforts = append(unapprovedEfforts, effort)
+				}
+
+				if !s.approvedOnly || len(unapprovedEfforts) == 0 {
+					issues = append(issues, issue)
+					continue
+				}
+
+				if len(efforts) > 0 {
+					approvedIssue := issue
+					approvedIssue.Efforts = efforts
+					issues = append(issues, approvedIssue)
+				}
+
+				unapprovedIssue := issue
+				unapprovedIssue.Unapproved = true
+				unapprovedIssue.Efforts = unapprovedEfforts
+				issues = append(issues, unapprovedIssue)
+			}
+			user.Issues = issues
+
+			if unapprovedSeconds > 0 {
+				hours := fmt.Sprintf("%.2f", float64(unapprovedSeconds)/3600)
+				if s.approvedOnly {
+					log.Println("Unapproved time of", user.DisplayName, "in", project.Key, "project is not billed:", hours, "hours")
+				} else {
+					log.Println("Warning: unapproved time of", user.DisplayName, "in", project.Key, "project is included:", hours, "hours,", user.Approval)
+				}
+			}
+		}
+	}
+}
+
+// getUserStatus returns the least approved status of the periods.
+func (s *ApprovalService) getUserStatus(approvals []models.TempoApproval) string {
+	if len(approvals) == 0 {
+		return constants.ApprovalStatusOpen
+	}
+
+	status := constants.ApprovalStatusApproved
+	for _, approval := range approvals {
+		approvalStatus := s.getApprovalStatus(approval)
+		if s.getStatusOrder(approvalStatus) < s.getStatusOrder(status) {
+			status = approvalStatus
+		}
+	}
+
+	return status
+}
+
+func (s *ApprovalService) getStatus(approvals []models.TempoApproval, date string) string {
+	for _, approval := range approvals {
+		if approval.Period.From <= date && date <= approval.Period.To {
+			return s.getApprovalStatus(approval)
+		}
+	}
+
+	return constants.ApprovalStatusOpen
+}
+
+func (s *ApprovalService) getApprovalStatus(approval models.TempoApproval) string {
+	switch approval.Status.Key {
+	case "APPROVED":
+		return constants.ApprovalStatusApproved
+	case "IN_REVIEW", "WAITING_FOR_APPROVAL":
+		return constants.ApprovalStatusSubmitted
+	default:
+		return constants.ApprovalStatusOpen
+	}
+}
+
+func (s *ApprovalService) getStatusOrder(status string) int {
+	for i, orderedStatus := range approvalStatusOrder {
+		if orderedStatus == status {
+			return i
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"reflect"
+	"tempo-worklog/constants"
+	"tempo-worklog/models"
+	"testing"
+)
+
+func newTestApproval(from, to, status string) models.TempoApproval {
+	return models.TempoApproval{
+		Period: models.TempoApprovalPeriod{From: from, To: to},
+		Status: models.TempoApprovalStatus{Key: status},
+	}
+}
+
+func TestGetUserStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		approvals []models.TempoApproval
+		want      string
+	}{
+		{"no periods", nil, "open"},
+		{"approved", []models.TempoApproval{newTestApproval("2023-01-02", "2023-01-08", "APPROVED")}, "approved"},
+		{"in review", []models.TempoApproval{newTestApproval("2023-01-02", "2023-01-08", "IN_REVIEW")}, "submitted"},
+		{"least approved", []models.TempoApproval{
+			newTestApproval("2023-01-02", "2023-01-08", "APPROVED"),
+			newTestApproval("2023-01-09", "2023-01-15", "WAITING_FOR_APPROVAL"),
+			newTestApproval("2023-01-16", "2023-01-22", "OPEN"),
+		}, "open"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := NewApprovalService(false).getUserStatus(test.approvals); got != test.want {
+				t.Errorf("getUserStatus() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestApprovalApply(t *testing.T) {
+	accountIdToApprovals := map[string][]models.TempoApproval{
+		"1": {newTestApproval("2023-01-02", "2023-01-08", "APPROVED")},
+		"2": {newTestApproval("2023-01-02", "2023-01-08", "IN_REVIEW")},
+	}
+
+	tests := []struct {
+		name         string
+		approvedOnly bool
+		want         map[string][]string // account id to dates of efforts, unapproved ones are marked
+		wantCosts    map[string]float64  // account id to cost
+	}{
+		{
+			name:      "all time",
+			want:      map[string][]string{"1": {"2023-01-02", "2023-01-09"}, "2": {"2023-01-02", "2023-01-09"}},
+			wantCosts: map[string]float64{"1": 20, "2": 20},
+		},
+		{
+			name:         "approved only",
+			approvedOnly: true,
+			want:         map[string][]string{"1": {"2023-01-02", "2023-01-09 unapproved"}, "2": {"2023-01-02 unapproved", "2023-01-09 unapproved"}},
+			wantCosts:    map[string]float64{"1": 10, "2": 0},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			worklog := newTestWorklog("PRJ")
+			project := &worklog.Projects[0]
+			project.Users[0].Issues[0].Efforts = append(project.Users[0].Issues[0].Efforts,
+				models.Effort{Date: "2023-01-09", TimeSpentSeconds: 3600})
+			project.Users = append(project.Users, project.Users[0])
+			project.Users[1].AccountId = "2"
+
+			NewApprovalService(test.approvedOnly).Apply(worklog, accountIdToApprovals)
+
+			got := map[string][]string{}
+			for _, user := range project.Users {
+				for _, issue := range user.Issues {
+					for _, effort := range issue.Efforts {
+						date := effort.Date
+						if issue.Unapproved {
+							date += " unapproved"
+						}
+						got[user.AccountId] = append(got[user.AccountId], date)
+					}
+				}
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Apply() kept efforts %v, want %v", got, test.want)
+			}
+
+			pricingService := NewPricingService(constants.GranularityDay)
+			dateToPeriod := map[string]int{"2023-01-02": 0, "2023-01-09": 1}
+			wantStatuses := []string{"approved", "submitted"}
+			for i, user := range project.Users {
+				if user.Approval != wantStatuses[i] {
+					t.Errorf("approval of user %s is %q, want %q", user.AccountId, user.Approval, wantStatuses[i])
+				}
+
+				userPrice, err := pricingService.getUserPrice(user, models.RoundingAppConfig{}, dateToPeriod)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if userPrice.Hours != 2 || userPrice.Cost != test.wantCosts[user.AccountId] {
+					t.Errorf("user %s has %v h and cost %v, want 2 h and cost %v", user.AccountId, userPrice.Hours, userPrice.Cost, test.wantCosts[user.AccountId])
+				}
+			}
+		})
+	}
+}
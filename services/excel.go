@@ -5,7 +5,7 @@ import (
 	"fmt"
 	"github.com/xuri/excelize/v2"
 	"log"
-	"math"
+	"sort"
 	"strconv"
 	"strings"
 	"tempo-worklog/constants"
@@ -14,37 +14,191 @@ import (
 )
 
 const (
-	ColumnReportDateFormat = "%d/%d"
+	ColumnReportDateFormat   = "%d/%d"
+	ColumnReportWeekFormat   = "%d/%d-%d/%d"
+	ColumnReportMonthFormat  = "Jan 2006"
+	ColumnReportDayWidth     = 6
+	ColumnReportWeekWidth    = 11
+	ColumnReportMonthWidth   = 10
+	ReportDaysInWeek         = 7
+	ReportFirstDayOfWeekDiff = 6 // shift to make Monday the first day of week
+	ReportNoTeamLabel        = "(no team)"
+	ReportNoEpicLabel        = "(no epic)"
+	ReportUnapprovedLabel    = "(unapproved)"
+	ReportNoAttributeLabel   = "(no %s)"
+	ReportIssueFieldWidth    = 15
+	ReportProjectSheetSuffix = " project"
 )
 
+var ReportColumnDefaults = map[string]models.ReportColumnAppConfig{
+	constants.ColumnName:     {Name: constants.ColumnName, Header: "Name", Width: 30},
+	constants.ColumnPosition: {Name: constants.ColumnPosition, Header: "Position", Width: 30},
+	constants.ColumnTask:     {Name: constants.ColumnTask, Header: "Task", Width: 60},
+	constants.ColumnRate:     {Name: constants.ColumnRate, Header: "Rate", Width: 10},
+	constants.ColumnHours:    {Name: constants.ColumnHours, Header: "Hours", Width: 10},
+	constants.ColumnCost:     {Name: constants.ColumnCost, Header: "Total cost", Width: 20},
+	constants.ColumnApproval: {Name: constants.ColumnApproval, Header: "Approval", Width: 12},
+
+	constants.ColumnLoggedHours:   {Name: constants.ColumnLoggedHours, Header: "Logged hours", Width: 15},
+	constants.ColumnBillableHours: {Name: constants.ColumnBillableHours, Header: "Billable hours", Width: 15},
+	constants.ColumnBillableCost:  {Name: constants.ColumnBillableCost, Header: "Billable cost", Width: 20},
+}
+
 type ExcelService struct {
-	filePath string
+	filePath         string
+	templateFilePath string
+	reportConfig     models.ReportAppConfig
+	layoutConfig     models.ReportLayoutAppConfig
+	pricingService   *PricingService
 }
 
-func NewExcelService(filePath string) *ExcelService {
-	return &ExcelService{filePath: filePath}
+func NewExcelService(filePath, templateFilePath string, reportConfig models.ReportAppConfig,
+	layoutConfig models.ReportLayoutAppConfig, pricingService *PricingService) *ExcelService {
+	return &ExcelService{
+		filePath:         filePath,
+		templateFilePath: templateFilePath,
+		reportConfig:     reportConfig,
+		layoutConfig:     layoutConfig,
+		pricingService:   pricingService,
+	}
 }
 
 func (s *ExcelService) Save(worklog *models.Worklog, dateFrom, dateTo string) error {
-	f := excelize.NewFile()
+	f, err := s.openFile()
+	if err != nil {
+		return err
+	}
 
 	sheet, err := s.getSheetName(dateFrom, dateTo)
 	if err != nil {
 		return err
 	}
 
-	// prepare
-	err = s.prepare(f, *sheet, dateFrom, dateTo)
+	periods, err := s.pricingService.getPeriods(dateFrom, dateTo)
 	if err != nil {
 		return err
 	}
 
-	// fill data
-	err = s.fill(f, *sheet, dateFrom, dateTo, worklog)
+	columns, err := s.getColumns()
+	if err != nil {
+		return err
+	}
+
+	if s.reportConfig.Epics && len(s.reportConfig.GroupBy) > 0 {
+		return errors.New("report can be grouped either by epics or by work attribute")
+	}
+
+	gridAnchor, err := s.getTemplateAnchor(f, TemplateGridName)
+	if err != nil {
+		return err
+	}
+
+	// summary
+	if len(s.templateFilePath) == 0 {
+		f.SetSheetName("Sheet1", SummarySheetName)
+	} else {
+		_, err = f.NewSheet(SummarySheetName)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = s.prepareSummary(f, SummarySheetName)
 	if err != nil {
 		return err
 	}
 
+	err = s.fillSummary(f, SummarySheetName, worklog, s.pricingService.getDateToPeriod(periods))
+	if err != nil {
+		return err
+	}
+
+	// details
+	err = s.fillTemplateHeader(f, *sheet)
+	if err != nil {
+		return err
+	}
+
+	var contexts []*models.ExcelContext
+
+	switch s.reportConfig.Layout {
+	case constants.LayoutSingle:
+		anchor := models.ExcelAnchor{Sheet: *sheet, ColumnIndex: 1, RowIndex: 1}
+		if gridAnchor != nil {
+			anchor = *gridAnchor
+		}
+
+		context, err := s.createDetailSheet(f, anchor, columns, periods, worklog)
+		if err != nil {
+			return err
+		}
+		contexts = []*models.ExcelContext{context}
+	case constants.LayoutPerProject:
+		if gridAnchor != nil {
+			return errors.New("report template grid is supported by single layout only")
+		}
+		if worklog.By == constants.ReportByUser {
+			return errors.New("user report is supported by single layout only")
+		}
+
+		contexts, err = s.createProjectDetailSheets(f, columns, periods, worklog)
+		if err != nil {
+			return err
+		}
+	default:
+		return errors.New("unknown report layout: " + s.reportConfig.Layout)
+	}
+
+	// template totals
+	err = s.fillTemplateTotals(f, contexts)
+	if err != nil {
+		return err
+	}
+
+	// flat data
+	if s.reportConfig.DataSheet {
+		dataLastRowIndex, err := s.createDataSheet(f, DataSheetName, worklog)
+		if err != nil {
+			return err
+		}
+
+		if s.reportConfig.PivotTable && dataLastRowIndex > 1 {
+			err = s.createPivotSheet(f, PivotSheetName, DataSheetName, dataLastRowIndex)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// worklog descriptions
+	if s.reportConfig.WorklogsSheet {
+		err = s.createWorklogsSheet(f, WorklogsSheetName, worklog)
+		if err != nil {
+			return err
+		}
+	}
+
+	// planned vs actual
+	if s.reportConfig.Plans {
+		err = s.preparePlan(f, PlanSheetName)
+		if err != nil {
+			return err
+		}
+
+		err = s.fillPlan(f, PlanSheetName, worklog, s.pricingService.getDateToPeriod(periods))
+		if err != nil {
+			return err
+		}
+	}
+
+	// charts
+	if s.reportConfig.Charts {
+		err = s.createChartsSheet(f, ChartsSheetName, contexts)
+		if err != nil {
+			return err
+		}
+	}
+
 	// save to file
 	err = f.SaveAs(s.filePath)
 	if err != nil {
@@ -74,227 +228,885 @@ func (s *ExcelService) getSheetName(dateFrom, dateTo string) (*string, error) {
 	return &sheetName, nil
 }
 
-func (s *ExcelService) prepare(f *excelize.File, sheet, dateFrom, dateTo string) error {
-	f.SetSheetName("Sheet1", sheet)
+// createDetailSheet writes the detailed worklog starting from the anchor cell,
+// rows of existing sheet above the anchor are kept.
+func (s *ExcelService) createDetailSheet(f *excelize.File, anchor models.ExcelAnchor, columns []models.ReportColumnAppConfig,
+	periods []models.ReportPeriod, worklog *models.Worklog) (*models.ExcelContext, error) {
+	_, err := f.NewSheet(anchor.Sheet)
+	if err != nil {
+		return nil, err
+	}
 
-	alignment := excelize.Alignment{Horizontal: "center", Vertical: "center"}
-	font := excelize.Font{Size: 13, Color: "#000000", Bold: true}
-	style1, err := f.NewStyle(&excelize.Style{Alignment: &alignment, Font: &font})
-	err = f.SetRowStyle(sheet, 1, 1, style1)
+	context, err := s.getContext(f, anchor, columns, periods)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	context.By = worklog.By
+
+	// rows above the anchor are read before the stream writer replaces them
+	templateRows, err := s.getTemplateRows(f, context.Sheet, anchor.RowIndex-1)
+	if err != nil {
+		return nil, err
 	}
 
-	headerBorders := []excelize.Border{
+	// https://xuri.me/excelize/en/stream.html
+	sw, err := f.NewStreamWriter(context.Sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	// prepare
+	err = s.setTemplateColWidths(f, sw, context)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.prepare(sw, templateRows, context)
+	if err != nil {
+		return nil, err
+	}
+
+	// fill data
+	err = s.fill(f, sw, worklog, context)
+	if err != nil {
+		return nil, err
+	}
+
+	err = sw.Flush()
+	if err != nil {
+		return nil, err
+	}
+
+	return context, nil
+}
+
+func (s *ExcelService) createProjectDetailSheets(f *excelize.File, columns []models.ReportColumnAppConfig,
+	periods []models.ReportPeriod, worklog *models.Worklog) ([]*models.ExcelContext, error) {
+	var contexts []*models.ExcelContext
+
+	for _, project := range worklog.Projects {
+		projectWorklog := &models.Worklog{Projects: []models.Project{project}}
+
+		anchor := models.ExcelAnchor{Sheet: s.getProjectSheetName(project.Key), ColumnIndex: 1, RowIndex: 1}
+
+		context, err := s.createDetailSheet(f, anchor, columns, periods, projectWorklog)
+		if err != nil {
+			return nil, err
+		}
+
+		contexts = append(contexts, context)
+	}
+
+	err := s.prepareTotals(f, TotalsSheetName)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.fillTotals(f, TotalsSheetName, contexts)
+	if err != nil {
+		return nil, err
+	}
+
+	return contexts, nil
+}
+
+// getProjectSheetName returns the project key as sheet name, keys clashing with names of other sheets get a suffix
+// because Excel compares sheet names case-insensitively.
+func (s *ExcelService) getProjectSheetName(projectKey string) string {
+	for _, sheet := range []string{SummarySheetName, TotalsSheetName, DataSheetName, PivotSheetName, WorklogsSheetName,
+		PlanSheetName, ChartsSheetName} {
+		if strings.EqualFold(projectKey, sheet) {
+			return projectKey + ReportProjectSheetSuffix
+		}
+	}
+
+	return projectKey
+}
+
+// getColumns validates configured columns of detail sheets and fills missing headers and widths.
+func (s *ExcelService) getColumns() ([]models.ReportColumnAppConfig, error) {
+	var columns []models.ReportColumnAppConfig
+	names := map[string]bool{}
+
+	for _, column := range s.layoutConfig.Columns {
+		name := strings.ToLower(column.Name)
+
+		defaults, ok := ReportColumnDefaults[name]
+		if !ok {
+			return nil, errors.New("unknown report column: " + column.Name)
+		}
+
+		if names[name] {
+			return nil, errors.New("duplicated report column: " + column.Name)
+		}
+		names[name] = true
+
+		column.Name = name
+		if len(column.Header) == 0 {
+			column.Header = defaults.Header
+		}
+		if column.Width <= 0 {
+			column.Width = defaults.Width
+		}
+
+		columns = append(columns, column)
+	}
+
+	// approval status of fetched approvals is shown even if the column is not configured
+	if (s.reportConfig.Approvals || s.reportConfig.ApprovedOnly) && !names[constants.ColumnApproval] {
+		names[constants.ColumnApproval] = true
+		columns = append(columns, ReportColumnDefaults[constants.ColumnApproval])
+	}
+
+	// Jira issue fields follow the configured columns
+	for _, field := range s.layoutConfig.IssueFields {
+		if len(field.Name) == 0 {
+			return nil, errors.New("report issue field id is empty")
+		}
+
+		name := constants.ColumnIssueFieldPrefix + field.Name
+		if names[name] {
+			return nil, errors.New("duplicated report issue field: " + field.Name)
+		}
+		names[name] = true
+
+		if len(field.Header) == 0 {
+			field.Header = field.Name
+		}
+		if field.Width <= 0 {
+			field.Width = ReportIssueFieldWidth
+		}
+		field.Name = name
+
+		columns = append(columns, field)
+	}
+
+	// totals and charts refer to hours and cost
+	for _, name := range []string{constants.ColumnHours, constants.ColumnCost} {
+		if !names[name] {
+			return nil, errors.New("report column is required: " + name)
+		}
+	}
+
+	return columns, nil
+}
+
+func (s *ExcelService) getSheetReference(sheet string) string {
+	return "'" + strings.ReplaceAll(sheet, "'", "''") + "'!"
+}
+
+func (s *ExcelService) getPeriodColumnWidth() float64 {
+	switch s.reportConfig.Granularity {
+	case constants.GranularityWeek:
+		return ColumnReportWeekWidth
+	case constants.GranularityMonth:
+		return ColumnReportMonthWidth
+	default:
+		return ColumnReportDayWidth
+	}
+}
+
+func (s *ExcelService) getContext(f *excelize.File, anchor models.ExcelAnchor, columns []models.ReportColumnAppConfig,
+	periods []models.ReportPeriod) (*models.ExcelContext, error) {
+	styles, err := s.getStyles(f)
+	if err != nil {
+		return nil, err
+	}
+
+	context := &models.ExcelContext{
+		Sheet:                anchor.Sheet,
+		Columns:              columns,
+		ColumnToIndex:        map[string]int{},
+		FirstColumnIndex:     anchor.ColumnIndex,
+		FirstDateColumnIndex: anchor.ColumnIndex + len(columns),
+		ColsCount:            anchor.ColumnIndex - 1 + len(columns) + len(periods),
+		LastRowIndex:         0,
+		Periods:              periods,
+		DateToPeriod:         s.pricingService.getDateToPeriod(periods),
+		Styles:               *styles,
+	}
+
+	for i, column := range columns {
+		context.ColumnToIndex[column.Name] = context.FirstColumnIndex + i
+	}
+
+	return context, nil
+}
+
+// getStyles creates all styles of detail sheet once, the stream writer refers them by id.
+func (s *ExcelService) getStyles(f *excelize.File) (*models.ExcelStyles, error) {
+	borders := []excelize.Border{
 		{Type: "top", Color: "#000000", Style: 1},
 		{Type: "left", Color: "#000000", Style: 1},
 		{Type: "bottom", Color: "#000000", Style: 1},
 		{Type: "right", Color: "#000000", Style: 1},
 	}
-	headerFont := excelize.Font{Size: 13, Color: "#ffffff", Bold: true}
-	headerFill := excelize.Fill{Color: []string{"#2487bc"}, Type: "pattern", Pattern: 3}
-	style, err := f.NewStyle(&excelize.Style{Alignment: &alignment, Font: &headerFont, Border: headerBorders, Fill: headerFill})
-	err = f.SetCellStyle(sheet, "A1", "F1", style)
+	center := excelize.Alignment{Horizontal: "center", Vertical: "center"}
+	theme := s.layoutConfig.Theme
+	headerFont := excelize.Font{Family: theme.FontFamily, Size: theme.HeaderFontSize, Color: theme.HeaderFontColor, Bold: true}
+	headerPeriodFont := excelize.Font{Family: theme.FontFamily, Size: theme.HeaderFontSize, Color: theme.FontColor, Bold: true}
+	font := excelize.Font{Family: theme.FontFamily, Size: theme.FontSize, Color: theme.FontColor, Bold: true}
+	rowFont := excelize.Font{Family: theme.FontFamily, Size: theme.FontSize, Color: theme.FontColor}
+	groupFont := excelize.Font{Family: theme.FontFamily, Size: theme.FontSize, Color: theme.FontColor, Bold: true, Italic: true}
+	groupPeriodFont := excelize.Font{Family: theme.FontFamily, Size: theme.FontSize, Color: theme.FontColor, Italic: true}
+	headerFill := s.getThemeFill(theme.HeaderColor)
+	projectFill := s.getThemeFill(theme.ProjectColor)
+	teamFill := s.getThemeFill(theme.TeamColor)
+	userFill := s.getThemeFill(theme.UserColor)
+	epicFill := s.getThemeFill(theme.EpicColor)
+	totalFill := s.getThemeFill(theme.TotalColor)
+	weekendFill := s.getThemeFill(theme.WeekendColor)
+
+	styles := &models.ExcelStyles{}
+	var err error
+
+	styles.Header, err = f.NewStyle(&excelize.Style{Alignment: &center, Font: &headerFont, Border: borders, Fill: headerFill})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = f.SetRowHeight(sheet, 1, 25)
+	styles.HeaderPeriod, err = f.NewStyle(&excelize.Style{Alignment: &center, Font: &headerPeriodFont, Border: borders})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = f.SetCellValue(sheet, "A1", "Name")
+	styles.HeaderWeekend, err = f.NewStyle(&excelize.Style{Alignment: &center, Font: &font, Border: borders, Fill: weekendFill})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = f.SetCellValue(sheet, "B1", "Position")
+	styles.Project, err = f.NewStyle(&excelize.Style{Alignment: &excelize.Alignment{Vertical: "center"}, Font: &font, Fill: projectFill})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = f.SetCellValue(sheet, "C1", "Task")
+	styles.Team, err = f.NewStyle(&excelize.Style{Font: &font, Fill: teamFill})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = f.SetCellValue(sheet, "D1", "Rate")
+	styles.TeamCost, err = f.NewStyle(&excelize.Style{Font: &font, Fill: teamFill, NumFmt: 177})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = f.SetCellValue(sheet, "E1", "Hours")
+	styles.TeamPeriod, err = f.NewStyle(&excelize.Style{Alignment: &excelize.Alignment{Horizontal: "center"}, Font: &font, Fill: teamFill})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = f.SetCellValue(sheet, "F1", "Total cost")
+	styles.User, err = f.NewStyle(&excelize.Style{Font: &font, Fill: userFill})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = f.SetColWidth(sheet, "A", "A", 30)
+	styles.UserRate, err = f.NewStyle(&excelize.Style{Font: &font, Fill: userFill, NumFmt: 177})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = f.SetColWidth(sheet, "B", "B", 30)
+	styles.UserHours, err = f.NewStyle(&excelize.Style{Font: &rowFont, Fill: userFill})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = f.SetColWidth(sheet, "C", "C", 60)
+	styles.UserCost, err = f.NewStyle(&excelize.Style{Font: &rowFont, Fill: userFill, NumFmt: 177})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = f.SetColWidth(sheet, "D", "D", 10)
+	styles.UserPeriod, err = f.NewStyle(&excelize.Style{Alignment: &excelize.Alignment{Horizontal: "center"}, Font: &font, Fill: userFill})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = f.SetColWidth(sheet, "E", "E", 10)
+	styles.Group, err = f.NewStyle(&excelize.Style{Font: &groupFont, Fill: epicFill})
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	styles.GroupCost, err = f.NewStyle(&excelize.Style{Font: &groupFont, Fill: epicFill, NumFmt: 177})
+	if err != nil {
+		return nil, err
+	}
+
+	styles.GroupPeriod, err = f.NewStyle(&excelize.Style{Alignment: &excelize.Alignment{Horizontal: "center"}, Font: &groupPeriodFont, Fill: epicFill})
+	if err != nil {
+		return nil, err
+	}
+
+	styles.IssueTask, err = f.NewStyle(&excelize.Style{Alignment: &excelize.Alignment{WrapText: true}, Font: &rowFont})
+	if err != nil {
+		return nil, err
+	}
+
+	styles.IssueRate, err = f.NewStyle(&excelize.Style{Font: &rowFont, NumFmt: 177})
+	if err != nil {
+		return nil, err
+	}
+
+	styles.IssueCost, err = f.NewStyle(&excelize.Style{Font: &rowFont, NumFmt: 177})
+	if err != nil {
+		return nil, err
+	}
+
+	styles.IssuePeriod, err = f.NewStyle(&excelize.Style{Alignment: &excelize.Alignment{Horizontal: "center"}, Font: &rowFont})
+	if err != nil {
+		return nil, err
+	}
+
+	styles.Weekend, err = f.NewStyle(&excelize.Style{Alignment: &excelize.Alignment{Horizontal: "center"}, Font: &rowFont, Fill: weekendFill})
+	if err != nil {
+		return nil, err
 	}
 
-	err = f.SetColWidth(sheet, "F", "F", 20)
+	styles.Total, err = f.NewStyle(&excelize.Style{Alignment: &center, Font: &font, Fill: totalFill})
+	if err != nil {
+		return nil, err
+	}
+
+	styles.TotalCost, err = f.NewStyle(&excelize.Style{Alignment: &center, Font: &font, Fill: totalFill, NumFmt: 177})
+	if err != nil {
+		return nil, err
+	}
+
+	return styles, nil
+}
+
+func (s *ExcelService) getThemeFill(color string) excelize.Fill {
+	if len(color) == 0 {
+		return excelize.Fill{}
+	}
+
+	return excelize.Fill{Color: []string{color}, Type: "pattern", Pattern: 3}
+}
+
+func (s *ExcelService) prepare(sw *excelize.StreamWriter, templateRows []models.ExcelTemplateRow, context *models.ExcelContext) error {
+	for i, column := range context.Columns {
+		err := sw.SetColWidth(context.FirstColumnIndex+i, context.FirstColumnIndex+i, column.Width)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(context.Periods) > 0 {
+		err := sw.SetColWidth(context.FirstDateColumnIndex, context.ColsCount, s.getPeriodColumnWidth())
+		if err != nil {
+			return err
+		}
+	}
+
+	headerRowIndex := len(templateRows) + 1
+
+	topLeftCell, err := excelize.CoordinatesToCellName(context.FirstDateColumnIndex, headerRowIndex+1)
 	if err != nil {
 		return err
 	}
 
 	// https://xuri.me/excelize/en/utils.html#SetPanes
-	err = f.SetPanes(sheet, &excelize.Panes{Freeze: true, XSplit: 6, YSplit: 1})
+	err = sw.SetPanes(&excelize.Panes{
+		Freeze:      true,
+		XSplit:      context.FirstDateColumnIndex - 1,
+		YSplit:      headerRowIndex,
+		TopLeftCell: topLeftCell,
+		ActivePane:  "bottomRight",
+	})
 	if err != nil {
 		return err
 	}
 
-	// dates
-	startDate, err := time.Parse(constants.InputDateFormat, dateFrom)
+	err = s.writeTemplateRows(sw, templateRows, context)
 	if err != nil {
 		return err
 	}
 
-	endDate, err := time.Parse(constants.InputDateFormat, dateTo)
+	row := make([]interface{}, context.ColsCount)
+
+	for i, column := range context.Columns {
+		row[context.FirstColumnIndex-1+i] = excelize.Cell{StyleID: context.Styles.Header, Value: column.Header}
+	}
+
+	// periods
+	for i, period := range context.Periods {
+		style := context.Styles.HeaderPeriod
+		if period.IsWeekend {
+			style = context.Styles.HeaderWeekend
+		}
+
+		row[context.FirstDateColumnIndex+i-1] = excelize.Cell{StyleID: style, Value: period.Label}
+	}
+
+	return s.writeRow(sw, row, excelize.RowOpts{Height: 25}, context)
+}
+
+func (s *ExcelService) fill(f *excelize.File, sw *excelize.StreamWriter, worklog *models.Worklog, context *models.ExcelContext) error {
+	err := s.layout(worklog, context)
 	if err != nil {
 		return err
 	}
 
-	i := 6
-	for date := startDate; !date.After(endDate); date = date.AddDate(0, 0, 1) {
-		formattedDate := fmt.Sprintf(ColumnReportDateFormat, date.Month(), date.Day())
-		i++
+	for _, row := range context.Rows {
+		switch row.Role {
+		case constants.RowRoleProject:
+			log.Println("Creating report for project:", row.ProjectKey)
+			err = s.fillProjectRow(sw, row, context)
+		case constants.RowRoleTeam:
+			err = s.fillSubtotalRow(sw, row, row.Team, context)
+		case constants.RowRoleAccount:
+			log.Println("Creating report for user:", row.User.DisplayName)
+			err = s.fillSubtotalRow(sw, row, row.User.DisplayName, context)
+		case constants.RowRoleUser:
+			log.Println("Processing issues for user:", row.User.DisplayName, fmt.Sprintf("(%d)", len(row.Children)))
+			err = s.fillUserRow(sw, row, context)
+		case constants.RowRoleGroup:
+			err = s.fillGroupRow(sw, row, context)
+		case constants.RowRoleIssue:
+			err = s.fillIssueRow(sw, row, context)
+		case constants.RowRoleTotal:
+			err = s.fillTotalRow(sw, row, context)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return s.finalize(f, context)
+}
 
-		cell, err := excelize.CoordinatesToCellName(i, 1)
+// layout plans rows of the sheet with their roles and calculates hours and cost of each row.
+func (s *ExcelService) layout(worklog *models.Worklog, context *models.ExcelContext) error {
+	totalRow := models.ExcelRow{Role: constants.RowRoleTotal, ColumnToHours: map[int]float64{}}
+
+	var rowIndex int
+	var err error
+	if worklog.By == constants.ReportByUser {
+		rowIndex, err = s.layoutAccounts(worklog, &totalRow, context)
+	} else {
+		rowIndex, err = s.layoutProjects(worklog, &totalRow, context)
+	}
+	if err != nil {
+		return err
+	}
+
+	rowIndex++
+	totalRow.Index = rowIndex
+	context.Rows = append(context.Rows, totalRow)
+
+	context.TotalHours = totalRow.Hours
+	context.TotalCost = totalRow.Cost
+
+	return nil
+}
+
+// layoutProjects plans project rows followed by team and user rows of the project, returns the last row index.
+func (s *ExcelService) layoutProjects(worklog *models.Worklog, totalRow *models.ExcelRow, context *models.ExcelContext) (int, error) {
+	rowIndex := context.LastRowIndex
+
+	for _, project := range worklog.Projects {
+		rowIndex++
+		context.Rows = append(context.Rows, models.ExcelRow{Index: rowIndex, Role: constants.RowRoleProject, ProjectKey: project.Key})
+
+		for _, team := range s.getTeams(project.Users) {
+			teamRowPosition := -1 // position of team row in context rows
+			if s.reportConfig.Teams {
+				rowIndex++
+				teamRow := models.ExcelRow{
+					Index:         rowIndex,
+					Role:          constants.RowRoleTeam,
+					ProjectKey:    project.Key,
+					Team:          team,
+					ColumnToHours: map[int]float64{},
+				}
+				context.Rows = append(context.Rows, teamRow)
+				teamRowPosition = len(context.Rows) - 1
+			}
+
+			for _, user := range project.Users {
+				if s.reportConfig.Teams && s.getTeam(user) != team {
+					continue
+				}
+
+				rows, err := s.layoutUser(project, user, rowIndex, context)
+				if err != nil {
+					return 0, err
+				}
+				rowIndex += len(rows)
+				context.Rows = append(context.Rows, rows...)
+
+				userRow := rows[0]
+				s.addChildRow(totalRow, userRow)
+				if teamRowPosition >= 0 {
+					s.addChildRow(&context.Rows[teamRowPosition], userRow)
+				}
+			}
+		}
+	}
+
+	return rowIndex, nil
+}
+
+// layoutAccounts plans account rows with subtotals followed by user rows of each project of the user,
+// returns the last row index.
+func (s *ExcelService) layoutAccounts(worklog *models.Worklog, totalRow *models.ExcelRow, context *models.ExcelContext) (int, error) {
+	rowIndex := context.LastRowIndex
+
+	for _, account := range s.getAccounts(worklog) {
+		rowIndex++
+		context.Rows = append(context.Rows, models.ExcelRow{
+			Index:         rowIndex,
+			Role:          constants.RowRoleAccount,
+			User:          account,
+			ColumnToHours: map[int]float64{},
+		})
+		accountRowPosition := len(context.Rows) - 1
+
+		for _, project := range worklog.Projects {
+			for _, user := range project.Users {
+				if user.AccountId != account.AccountId {
+					continue
+				}
+
+				rows, err := s.layoutUser(project, user, rowIndex, context)
+				if err != nil {
+					return 0, err
+				}
+				rowIndex += len(rows)
+				context.Rows = append(context.Rows, rows...)
+
+				userRow := rows[0]
+				s.addChildRow(totalRow, userRow)
+				s.addChildRow(&context.Rows[accountRowPosition], userRow)
+			}
+		}
+	}
+
+	return rowIndex, nil
+}
+
+// getAccounts returns users of all projects ordered by name.
+func (s *ExcelService) getAccounts(worklog *models.Worklog) []models.User {
+	var accounts []models.User
+	accountIdToExists := map[string]bool{}
+
+	for _, project := range worklog.Projects {
+		for _, user := range project.Users {
+			if !accountIdToExists[user.AccountId] {
+				accountIdToExists[user.AccountId] = true
+				accounts = append(accounts, user)
+			}
+		}
+	}
+
+	sort.SliceStable(accounts, func(i, j int) bool {
+		return strings.ToLower(accounts[i].DisplayName) < strings.ToLower(accounts[j].DisplayName)
+	})
+
+	return accounts
+}
+
+// layoutUser plans the user row followed by issue rows of the user after the given row,
+// issue rows are nested under group rows if grouping by epics or work attribute is on.
+func (s *ExcelService) layoutUser(project models.Project, user models.User, rowIndex int, context *models.ExcelContext) ([]models.ExcelRow, error) {
+	rowIndex++
+	userRow := models.ExcelRow{
+		Index:      rowIndex,
+		Role:       constants.RowRoleUser,
+		ProjectKey: project.Key,
+		Rounding:   project.Rounding,
+		User:       user,
+		Rate:       user.Rate,
+	}
+
+	userPrice, err := s.pricingService.getUserPrice(user, project.Rounding, context.DateToPeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	userRow.ColumnToHours = s.getColumnToHours(userPrice.PeriodToHours, context)
+	userRow.WeekendPeriods = userPrice.WeekendPeriods
+	userRow.Hours = userPrice.Hours
+	userRow.Cost = userPrice.Cost
+	userRow.LoggedHours = userPrice.LoggedHours
+	userRow.BillableHours = userPrice.BillableHours
+	userRow.BillableCost = userPrice.BillableCost
+
+	rows := []models.ExcelRow{userRow}
+
+	for _, group := range s.getIssueGroups(user.Issues) {
+		groupRowPosition := -1 // position of group row in user rows
+		if len(group.Label) > 0 {
+			rowIndex++
+			groupRow := models.ExcelRow{
+				Index:         rowIndex,
+				Role:          constants.RowRoleGroup,
+				Label:         group.Label,
+				ProjectKey:    project.Key,
+				User:          user,
+				ColumnToHours: map[int]float64{},
+			}
+			rows = append(rows, groupRow)
+			groupRowPosition = len(rows) - 1
+		}
+
+		for _, issue := range group.Issues {
+			issuePrice, err := s.pricingService.getIssuePrice(user, issue, project.Rounding, context.DateToPeriod)
+			if err != nil {
+				return nil, err
+			}
+
+			rowIndex++
+			issueRow := models.ExcelRow{
+				Index:          rowIndex,
+				Role:           constants.RowRoleIssue,
+				ProjectKey:     project.Key,
+				Rounding:       project.Rounding,
+				User:           user,
+				Rate:           issuePrice.Rate,
+				Issue:          issue,
+				ColumnToHours:  s.getColumnToHours(issuePrice.PeriodToHours, context),
+				WeekendPeriods: issuePrice.WeekendPeriods,
+				Hours:          issuePrice.Hours,
+				Cost:           issuePrice.Cost,
+				LoggedHours:    issuePrice.LoggedHours,
+				BillableHours:  issuePrice.BillableHours,
+				BillableCost:   issuePrice.BillableCost,
+			}
+			rows = append(rows, issueRow)
+
+			if groupRowPosition >= 0 {
+				s.addChildRow(&rows[groupRowPosition], issueRow)
+			}
+
+			rows[0].Children = append(rows[0].Children, issueRow.Index)
+		}
+	}
+
+	return rows, nil
+}
+
+// getColumnToHours places hours of report periods to columns of the sheet.
+func (s *ExcelService) getColumnToHours(periodToHours map[int]float64, context *models.ExcelContext) map[int]float64 {
+	columnToHours := map[int]float64{}
+	for period, hours := range periodToHours {
+		columnToHours[context.FirstDateColumnIndex+period] = hours
+	}
+
+	return columnToHours
+}
+
+// fillBillingCells puts logged and billable hours and billable cost to the row,
+// issue rows keep the values while other rows sum up their children.
+func (s *ExcelService) fillBillingCells(row []interface{}, excelRow models.ExcelRow, hoursStyle, costStyle int,
+	context *models.ExcelContext) error {
+	columnToValue := map[string]float64{
+		constants.ColumnLoggedHours:   excelRow.LoggedHours,
+		constants.ColumnBillableHours: excelRow.BillableHours,
+		constants.ColumnBillableCost:  excelRow.BillableCost,
+	}
+
+	for _, column := range []string{constants.ColumnLoggedHours, constants.ColumnBillableHours, constants.ColumnBillableCost} {
+		index, ok := context.ColumnToIndex[column]
+		if !ok {
+			continue
+		}
+
+		col, err := excelize.ColumnNumberToName(index)
 		if err != nil {
 			return err
 		}
 
-		err = f.SetCellValue(sheet, cell, formattedDate)
+		style := hoursStyle
+		if column == constants.ColumnBillableCost {
+			style = costStyle
+		}
+
+		value := columnToValue[column]
+
+		switch {
+		case excelRow.Role != constants.RowRoleIssue:
+			row[index-1] = s.getFormulaCell(style, s.getSumFormula(col, excelRow.Children), value)
+		case column == constants.ColumnBillableCost:
+			formula, err := s.getBillableCostFormula(excelRow, context)
+			if err != nil {
+				return err
+			}
+			row[index-1] = s.getFormulaCell(style, formula, value)
+		default:
+			row[index-1] = excelize.Cell{StyleID: style, Value: value}
+		}
+	}
+
+	return nil
+}
+
+// getBillableCostFormula multiplies billable hours by rate like getCostFormula, hidden cells are replaced by values.
+func (s *ExcelService) getBillableCostFormula(row models.ExcelRow, context *models.ExcelContext) (string, error) {
+	hours := strconv.FormatFloat(row.BillableHours, 'f', -1, 64)
+	if _, ok := context.ColumnToIndex[constants.ColumnBillableHours]; ok {
+		cell, err := s.getColumnCellName(constants.ColumnBillableHours, row.Index, context)
+		if err != nil {
+			return "", err
+		}
+		hours = cell
+	}
+
+	rate := strconv.Itoa(row.Rate)
+	if _, ok := context.ColumnToIndex[constants.ColumnRate]; ok {
+		cell, err := s.getColumnCellName(constants.ColumnRate, row.Index, context)
 		if err != nil {
-			return err
+			return "", err
 		}
+		rate = cell
+	}
+
+	return rate + "*" + hours, nil
+}
 
-		col := strings.TrimRight(cell, "1")
+// getIssueGroups groups issues by epics or by work attribute values in order of the first issue of group,
+// issues without epic or value go last. All issues are put to the single group without label if grouping is off.
+func (s *ExcelService) getIssueGroups(issues []models.Issue) []models.ExcelIssueGroup {
+	var getGroup func(issue models.Issue) (string, string) // group key and label
 
-		err = f.SetColWidth(sheet, col, col, 6)
-		if err != nil {
-			return err
+	switch {
+	case s.reportConfig.Epics:
+		getGroup = func(issue models.Issue) (string, string) {
+			if len(issue.EpicKey) == 0 {
+				return "", ReportNoEpicLabel
+			}
+			return issue.EpicKey, issue.EpicKey + ": " + issue.EpicSummary
 		}
+	case len(s.reportConfig.GroupBy) > 0:
+		getGroup = func(issue models.Issue) (string, string) {
+			if len(issue.Attribute) == 0 {
+				return "", fmt.Sprintf(ReportNoAttributeLabel, s.reportConfig.GroupBy)
+			}
+			return issue.Attribute, issue.Attribute
+		}
+	default:
+		return []models.ExcelIssueGroup{{Issues: issues}}
+	}
 
-		alignment := excelize.Alignment{Horizontal: "center", Vertical: "center"}
-		style, err := f.NewStyle(&excelize.Style{Alignment: &alignment})
-		err = f.SetColStyle(sheet, col, style)
-		if err != nil {
-			return err
+	var groups []models.ExcelIssueGroup
+	keyToGroupPosition := map[string]int{}
+	noGroup := models.ExcelIssueGroup{}
+
+	for _, issue := range issues {
+		key, label := getGroup(issue)
+
+		if len(key) == 0 {
+			noGroup.Label = label
+			noGroup.Issues = append(noGroup.Issues, issue)
+			continue
 		}
 
-		style, err = f.NewStyle(&excelize.Style{Alignment: &alignment, Border: headerBorders, Font: &font})
-		err = f.SetCellStyle(sheet, cell, cell, style)
-		if err != nil {
-			return err
+		position, ok := keyToGroupPosition[key]
+		if !ok {
+			groups = append(groups, models.ExcelIssueGroup{Label: label})
+			position = len(groups) - 1
+			keyToGroupPosition[key] = position
 		}
+		groups[position].Issues = append(groups[position].Issues, issue)
 	}
 
-	return nil
+	if len(noGroup.Issues) > 0 {
+		groups = append(groups, noGroup)
+	}
+
+	return groups
 }
 
-func (s *ExcelService) fill(f *excelize.File, sheet string, dateFrom, dateTo string, worklog *models.Worklog) error {
-	colsCount, err := s.getColsCountInRow(f, sheet, 1)
-	if err != nil {
-		return err
-	}
-	//fmt.Println("colsCount", *colsCount)
+// addChildRow adds hours and cost of the row to the subtotal or total row.
+func (s *ExcelService) addChildRow(parent *models.ExcelRow, child models.ExcelRow) {
+	parent.Children = append(parent.Children, child.Index)
+	parent.Hours = s.pricingService.roundHours(parent.Hours + child.Hours)
+	parent.Cost += child.Cost
+	parent.LoggedHours = s.pricingService.roundHours(parent.LoggedHours + child.LoggedHours)
+	parent.BillableHours = s.pricingService.roundHours(parent.BillableHours + child.BillableHours)
+	parent.BillableCost += child.BillableCost
 
-	context := models.ExcelContext{
-		FirstDateColumnIndex: 7,
-		ColsCount:            *colsCount,
-		LastRowIndex:         1,
+	for column, hours := range child.ColumnToHours {
+		parent.ColumnToHours[column] = s.pricingService.roundHours(parent.ColumnToHours[column] + hours)
 	}
 
-	for _, project := range worklog.Projects {
-		log.Println("Creating report for project:", project.Key)
-
-		err = s.fillProjectRow(f, sheet, project.Key, &context)
-		if err != nil {
-			return err
+	for period := range child.WeekendPeriods {
+		if parent.WeekendPeriods == nil {
+			parent.WeekendPeriods = map[int]bool{}
 		}
+		parent.WeekendPeriods[period] = true
+	}
+}
 
-		for _, user := range project.Users {
-			log.Println("Processing issues for user:", user.DisplayName, fmt.Sprintf("(%d)", len(user.Issues)))
+// getTeams returns sorted teams of users, users without team go last,
+// all users are put to the single unnamed team if grouping by teams is off.
+func (s *ExcelService) getTeams(users []models.User) []string {
+	if !s.reportConfig.Teams {
+		return []string{""}
+	}
 
-			err = s.fillUserRow(f, sheet, user, &context)
-			if err != nil {
-				return err
-			}
+	teamToExists := map[string]bool{}
+	for _, user := range users {
+		teamToExists[s.getTeam(user)] = true
+	}
 
-			for _, issue := range user.Issues {
-				err = s.fillIssueRow(f, sheet, issue, user.Rate, &context)
-				if err != nil {
-					return err
-				}
-			}
+	teams := make([]string, 0, len(teamToExists))
+	for team := range teamToExists {
+		if team != ReportNoTeamLabel {
+			teams = append(teams, team)
 		}
 	}
+	sort.Slice(teams, func(i, j int) bool {
+		return strings.ToLower(teams[i]) < strings.ToLower(teams[j])
+	})
 
-	err = s.fillTotalRow(f, sheet, worklog, &context)
-	if err != nil {
-		return err
+	if teamToExists[ReportNoTeamLabel] {
+		teams = append(teams, ReportNoTeamLabel)
 	}
 
-	err = s.finalize(f, sheet, dateFrom, dateTo, &context)
-	if err != nil {
-		return err
+	return teams
+}
+
+func (s *ExcelService) getTeam(user models.User) string {
+	if len(user.Team) == 0 {
+		return ReportNoTeamLabel
 	}
 
-	return nil
+	return user.Team
 }
 
-func (s *ExcelService) getColsCountInRow(f *excelize.File, sheet string, row int) (*int, error) {
-	rows, err := f.Rows(sheet)
-	if err != nil {
-		return nil, err
-	}
+func (s *ExcelService) getRowsByRole(context *models.ExcelContext, role string) []models.ExcelRow {
+	var rows []models.ExcelRow
 
-	if !rows.Next() {
-		return nil, errors.New("no rows")
+	for _, row := range context.Rows {
+		if row.Role == role {
+			rows = append(rows, row)
+		}
 	}
 
-	rowColumns, err := rows.Columns()
-	if err != nil {
-		return nil, err
-	}
+	return rows
+}
 
-	if err = rows.Close(); err != nil {
-		return nil, err
+// getSumFormula sums cells of the column in given rows, adjacent rows are joined into ranges.
+func (s *ExcelService) getSumFormula(col string, rowIndexes []int) string {
+	var refs []string
+
+	for i := 0; i < len(rowIndexes); i++ {
+		first := rowIndexes[i]
+		for i+1 < len(rowIndexes) && rowIndexes[i+1] == rowIndexes[i]+1 {
+			i++
+		}
+		last := rowIndexes[i]
+
+		if first == last {
+			refs = append(refs, col+strconv.Itoa(first))
+		} else {
+			refs = append(refs, col+strconv.Itoa(first)+":"+col+strconv.Itoa(last))
+		}
 	}
 
-	result := len(rowColumns)
-	return &result, nil
+	return "sum(" + strings.Join(refs, ",") + ")"
 }
 
-func (s *ExcelService) fillProjectRow(f *excelize.File, sheet string, projectKey string, context *models.ExcelContext) error {
+func (s *ExcelService) writeRow(sw *excelize.StreamWriter, row []interface{}, opts excelize.RowOpts, context *models.ExcelContext) error {
 	context.LastRowIndex++
 
 	cell, err := excelize.CoordinatesToCellName(1, context.LastRowIndex)
@@ -302,167 +1114,224 @@ func (s *ExcelService) fillProjectRow(f *excelize.File, sheet string, projectKey
 		return err
 	}
 
-	cellTo, err := excelize.CoordinatesToCellName(context.ColsCount, context.LastRowIndex)
-	if err != nil {
-		return err
+	return sw.SetRow(cell, row, opts)
+}
+
+// setColumnCell puts the cell to the row if the column is shown in the sheet.
+func (s *ExcelService) setColumnCell(row []interface{}, column string, cell excelize.Cell, context *models.ExcelContext) {
+	if index, ok := context.ColumnToIndex[column]; ok {
+		row[index-1] = cell
 	}
+}
 
-	err = f.SetRowHeight(sheet, context.LastRowIndex, 25)
+func (s *ExcelService) getColumnCellName(column string, rowIndex int, context *models.ExcelContext) (string, error) {
+	return excelize.CoordinatesToCellName(context.ColumnToIndex[column], rowIndex)
+}
+
+// getCostFormula multiplies hours by rate cell of the row, the rate is put as is when its column is hidden.
+func (s *ExcelService) getCostFormula(row models.ExcelRow, context *models.ExcelContext) (string, error) {
+	hoursCell, err := s.getColumnCellName(constants.ColumnHours, row.Index, context)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	alignment := excelize.Alignment{Vertical: "center"}
-	font := excelize.Font{Size: 12, Color: "#000000", Bold: true}
-	fill := excelize.Fill{Color: []string{"#bee0f2"}, Type: "pattern", Pattern: 3}
-	style, err := f.NewStyle(&excelize.Style{Alignment: &alignment, Font: &font, Fill: fill})
-	err = f.SetCellStyle(sheet, cell, cellTo, style)
-	if err != nil {
-		return err
+	if _, ok := context.ColumnToIndex[constants.ColumnRate]; !ok {
+		return hoursCell + "*" + strconv.Itoa(row.Rate), nil
 	}
 
-	err = f.SetCellValue(sheet, cell, projectKey)
+	rateCell, err := s.getColumnCellName(constants.ColumnRate, row.Index, context)
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	return nil
+	return rateCell + "*" + hoursCell, nil
 }
 
-func (s *ExcelService) fillUserRow(f *excelize.File, sheet string, user models.User, context *models.ExcelContext) error {
-	context.LastRowIndex++
-
-	font := excelize.Font{Size: 12, Color: "#000000", Bold: true}
-	fill := excelize.Fill{Color: []string{"#d3e2ea"}, Type: "pattern", Pattern: 3}
-	style, err := f.NewStyle(&excelize.Style{Font: &font, Fill: fill})
-	err = f.SetRowStyle(sheet, context.LastRowIndex, context.LastRowIndex, style)
-	if err != nil {
-		return err
+func (s *ExcelService) fillProjectRow(sw *excelize.StreamWriter, projectRow models.ExcelRow, context *models.ExcelContext) error {
+	row := make([]interface{}, context.ColsCount)
+	for i := context.FirstColumnIndex - 1; i < len(row); i++ {
+		row[i] = excelize.Cell{StyleID: context.Styles.Project}
 	}
+	row[context.FirstColumnIndex-1] = excelize.Cell{StyleID: context.Styles.Project, Value: projectRow.ProjectKey}
+
+	s.applyWeekendStyle(row, projectRow.WeekendPeriods, context)
 
-	rowIndex := strconv.Itoa(context.LastRowIndex)
+	return s.writeRow(sw, row, excelize.RowOpts{Height: 25}, context)
+}
 
-	err = f.SetCellValue(sheet, "A"+rowIndex, user.DisplayName)
+// fillSubtotalRow puts subtotals of user rows of team or account.
+func (s *ExcelService) fillSubtotalRow(sw *excelize.StreamWriter, subtotalRow models.ExcelRow, label string, context *models.ExcelContext) error {
+	hoursCol, err := excelize.ColumnNumberToName(context.ColumnToIndex[constants.ColumnHours])
 	if err != nil {
 		return err
 	}
 
-	err = f.SetCellValue(sheet, "B"+rowIndex, user.Position)
+	costCol, err := excelize.ColumnNumberToName(context.ColumnToIndex[constants.ColumnCost])
 	if err != nil {
 		return err
 	}
 
-	font = excelize.Font{Size: 12, Color: "#000000", Bold: true}
-	fill = excelize.Fill{Color: []string{"#d3e2ea"}, Type: "pattern", Pattern: 3}
-	style, err = f.NewStyle(&excelize.Style{Font: &font, Fill: fill, NumFmt: 177})
-	err = f.SetCellStyle(sheet, "D"+rowIndex, "D"+rowIndex, style)
-	if err != nil {
-		return err
+	row := make([]interface{}, context.ColsCount)
+	for i := context.FirstColumnIndex - 1; i < len(row); i++ {
+		row[i] = excelize.Cell{StyleID: context.Styles.Team}
 	}
+	row[context.FirstColumnIndex-1] = excelize.Cell{StyleID: context.Styles.Team, Value: label}
+	s.setColumnCell(row, constants.ColumnHours, s.getFormulaCell(context.Styles.Team, s.getSumFormula(hoursCol, subtotalRow.Children), subtotalRow.Hours), context)
+	s.setColumnCell(row, constants.ColumnCost, s.getFormulaCell(context.Styles.TeamCost, s.getSumFormula(costCol, subtotalRow.Children), subtotalRow.Cost), context)
 
-	conditionalFormat, err := s.getConditionalFormat(f)
-	if err != nil {
-		return err
+	for column, hours := range subtotalRow.ColumnToHours {
+		col, err := excelize.ColumnNumberToName(column)
+		if err != nil {
+			return err
+		}
+
+		row[column-1] = s.getFormulaCell(context.Styles.TeamPeriod, s.getSumFormula(col, subtotalRow.Children), hours)
 	}
 
-	err = f.SetConditionalFormat(sheet, "D"+rowIndex, conditionalFormat)
+	err = s.fillBillingCells(row, subtotalRow, context.Styles.Team, context.Styles.TeamCost, context)
 	if err != nil {
 		return err
 	}
 
-	err = f.SetCellValue(sheet, "D"+rowIndex, user.Rate)
+	s.applyWeekendStyle(row, subtotalRow.WeekendPeriods, context)
+
+	return s.writeRow(sw, row, excelize.RowOpts{}, context)
+}
+
+// fillGroupRow puts group subtotals of issue rows.
+func (s *ExcelService) fillGroupRow(sw *excelize.StreamWriter, groupRow models.ExcelRow, context *models.ExcelContext) error {
+	hoursCol, err := excelize.ColumnNumberToName(context.ColumnToIndex[constants.ColumnHours])
 	if err != nil {
 		return err
 	}
 
-	// hours
-	fill = excelize.Fill{Color: []string{"#d3e2ea"}, Type: "pattern", Pattern: 3}
-	style, err = f.NewStyle(&excelize.Style{Fill: fill})
-	err = f.SetCellStyle(sheet, "E"+rowIndex, "E"+rowIndex, style)
+	costCol, err := excelize.ColumnNumberToName(context.ColumnToIndex[constants.ColumnCost])
 	if err != nil {
 		return err
 	}
 
-	firstCol, err := excelize.ColumnNumberToName(context.FirstDateColumnIndex)
-	if err != nil {
-		return err
+	row := make([]interface{}, context.ColsCount)
+	s.setColumnCell(row, constants.ColumnTask, excelize.Cell{StyleID: context.Styles.Group, Value: groupRow.Label}, context)
+	s.setColumnCell(row, constants.ColumnRate, excelize.Cell{StyleID: context.Styles.Group}, context)
+	s.setColumnCell(row, constants.ColumnHours, s.getFormulaCell(context.Styles.Group, s.getSumFormula(hoursCol, groupRow.Children), groupRow.Hours), context)
+	s.setColumnCell(row, constants.ColumnCost, s.getFormulaCell(context.Styles.GroupCost, s.getSumFormula(costCol, groupRow.Children), groupRow.Cost), context)
+
+	for column := context.FirstDateColumnIndex; column <= context.ColsCount; column++ {
+		col, err := excelize.ColumnNumberToName(column)
+		if err != nil {
+			return err
+		}
+
+		if hours, ok := groupRow.ColumnToHours[column]; ok {
+			row[column-1] = s.getFormulaCell(context.Styles.GroupPeriod, s.getSumFormula(col, groupRow.Children), hours)
+		} else {
+			row[column-1] = excelize.Cell{StyleID: context.Styles.GroupPeriod}
+		}
 	}
 
-	lastCol, err := excelize.ColumnNumberToName(context.ColsCount)
+	err = s.fillBillingCells(row, groupRow, context.Styles.Group, context.Styles.GroupCost, context)
 	if err != nil {
 		return err
 	}
 
-	formula := "sum(" + firstCol + rowIndex + ":" + lastCol + rowIndex + ")"
+	s.applyWeekendStyle(row, groupRow.WeekendPeriods, context)
+
+	return s.writeRow(sw, row, excelize.RowOpts{}, context)
+}
+
+func (s *ExcelService) fillUserRow(sw *excelize.StreamWriter, userRow models.ExcelRow, context *models.ExcelContext) error {
+	rowIndex := strconv.Itoa(userRow.Index)
 
-	err = f.SetCellFormula(sheet, "E"+rowIndex, formula)
+	firstCol, err := excelize.ColumnNumberToName(context.FirstDateColumnIndex)
 	if err != nil {
 		return err
 	}
 
-	// total cost
-	fill = excelize.Fill{Color: []string{"#d3e2ea"}, Type: "pattern", Pattern: 3}
-	style, err = f.NewStyle(&excelize.Style{Fill: fill, NumFmt: 177})
-	err = f.SetCellStyle(sheet, "F"+rowIndex, "F"+rowIndex, style)
+	lastCol, err := excelize.ColumnNumberToName(context.ColsCount)
 	if err != nil {
 		return err
 	}
 
-	err = f.SetCellFormula(sheet, "F"+rowIndex, "D"+rowIndex+"*"+"E"+rowIndex)
+	costFormula, err := s.getCostFormula(userRow, context)
 	if err != nil {
 		return err
 	}
 
-	for i := context.FirstDateColumnIndex; i <= context.ColsCount; i++ {
-		col, err := excelize.ColumnNumberToName(i)
+	// issues with rate overrides have own costs
+	if s.pricingService.hasRateOverrides(userRow.User) {
+		costCol, err := excelize.ColumnNumberToName(context.ColumnToIndex[constants.ColumnCost])
 		if err != nil {
 			return err
 		}
 
-		alignment := excelize.Alignment{Horizontal: "center"}
-		style, err := f.NewStyle(&excelize.Style{Alignment: &alignment, Font: &font, Fill: fill})
-		err = f.SetCellStyle(sheet, col+rowIndex, col+rowIndex, style)
-		if err != nil {
-			return err
+		costFormula = s.getSumFormula(costCol, userRow.Children)
+
+		// issue costs are scaled to user hours rounded by total scope
+		if s.pricingService.isTotalRounding(userRow.Rounding) {
+			hoursCol, err := excelize.ColumnNumberToName(context.ColumnToIndex[constants.ColumnHours])
+			if err != nil {
+				return err
+			}
+
+			hoursCell := hoursCol + rowIndex
+			issuesHoursFormula := s.getSumFormula(hoursCol, userRow.Children)
+			costFormula = "if(" + issuesHoursFormula + "=0," + costFormula + "," + costFormula + "*" + hoursCell + "/" + issuesHoursFormula + ")"
 		}
+	}
+
+	// user rows are headed by account rows in user report, so the project is shown instead of the user
+	name := userRow.User.DisplayName
+	if context.By == constants.ReportByUser {
+		name = userRow.ProjectKey
+	}
+
+	row := make([]interface{}, context.ColsCount)
+	s.setColumnCell(row, constants.ColumnName, excelize.Cell{StyleID: context.Styles.User, Value: name}, context)
+	s.setColumnCell(row, constants.ColumnPosition, excelize.Cell{StyleID: context.Styles.User, Value: userRow.User.Position}, context)
+	s.setColumnCell(row, constants.ColumnTask, excelize.Cell{StyleID: context.Styles.User}, context)
+	s.setColumnCell(row, constants.ColumnRate, excelize.Cell{StyleID: context.Styles.UserRate, Value: userRow.User.Rate}, context)
+	s.setColumnCell(row, constants.ColumnApproval, excelize.Cell{StyleID: context.Styles.User, Value: userRow.User.Approval}, context)
+	for _, field := range s.layoutConfig.IssueFields {
+		s.setColumnCell(row, constants.ColumnIssueFieldPrefix+field.Name, excelize.Cell{StyleID: context.Styles.User}, context)
+	}
 
-		columnDate, err := f.GetCellValue(sheet, col+"1")
+	// hours, rounded issue hours are summed up instead of periods
+	formula := "sum(" + firstCol + rowIndex + ":" + lastCol + rowIndex + ")"
+	if userRow.Rounding.Mode != constants.RoundingModeNone && userRow.Rounding.Scope == constants.RoundingScopeIssue {
+		hoursCol, err := excelize.ColumnNumberToName(context.ColumnToIndex[constants.ColumnHours])
 		if err != nil {
 			return err
 		}
 
-		isAnyValuePresent := false
-
-		for _, issue := range user.Issues {
-			for _, effort := range issue.Efforts {
-				date, err := time.Parse(constants.InputDateFormat, effort.Date)
-				if err != nil {
-					return err
-				}
+		formula = s.getSumFormula(hoursCol, userRow.Children)
+	}
+	formula = s.getRoundingFormula(formula, userRow.Rounding, constants.RoundingScopeTotal)
+	s.setColumnCell(row, constants.ColumnHours, s.getFormulaCell(context.Styles.UserHours, formula, userRow.Hours), context)
 
-				formattedDate := fmt.Sprintf(ColumnReportDateFormat, date.Month(), date.Day())
+	// total cost
+	s.setColumnCell(row, constants.ColumnCost, s.getFormulaCell(context.Styles.UserCost, costFormula, userRow.Cost), context)
 
-				if columnDate == formattedDate {
-					isAnyValuePresent = true
-					break
-				}
-			}
+	for i := context.FirstDateColumnIndex; i <= context.ColsCount; i++ {
+		col, err := excelize.ColumnNumberToName(i)
+		if err != nil {
+			return err
 		}
 
-		firstRowIndex := context.LastRowIndex + 1
-		lastRowIndex := context.LastRowIndex + len(user.Issues)
-
-		if isAnyValuePresent {
-			formula := "sum(" + col + strconv.Itoa(firstRowIndex) + ":" + col + strconv.Itoa(lastRowIndex) + ")"
-
-			err = f.SetCellFormula(sheet, col+rowIndex, formula, excelize.FormulaOpts{})
-			if err != nil {
-				return err
-			}
+		if hours, ok := userRow.ColumnToHours[i]; ok {
+			row[i-1] = s.getFormulaCell(context.Styles.UserPeriod, s.getSumFormula(col, userRow.Children), hours)
+		} else {
+			row[i-1] = excelize.Cell{StyleID: context.Styles.UserPeriod}
 		}
 	}
 
-	return nil
+	err = s.fillBillingCells(row, userRow, context.Styles.UserHours, context.Styles.UserCost, context)
+	if err != nil {
+		return err
+	}
+
+	s.applyWeekendStyle(row, userRow.WeekendPeriods, context)
+
+	return s.writeRow(sw, row, excelize.RowOpts{StyleID: context.Styles.User}, context)
 }
 
 func (s *ExcelService) getConditionalFormat(f *excelize.File) ([]excelize.ConditionalFormatOptions, error) {
@@ -477,251 +1346,177 @@ func (s *ExcelService) getConditionalFormat(f *excelize.File) ([]excelize.Condit
 	return []excelize.ConditionalFormatOptions{{Type: "cell", Criteria: "=", Format: format, Value: "0"}}, nil
 }
 
-func (s *ExcelService) fillIssueRow(f *excelize.File, sheet string, issue models.Issue, rate int, context *models.ExcelContext) error {
-	context.LastRowIndex++
-	rowIndex := strconv.Itoa(context.LastRowIndex)
+func (s *ExcelService) fillIssueRow(sw *excelize.StreamWriter, issueRow models.ExcelRow, context *models.ExcelContext) error {
+	rowIndex := strconv.Itoa(issueRow.Index)
+	issue := issueRow.Issue
 
 	//fmt.Println("issue", issue)
 
-	alignment := excelize.Alignment{WrapText: true}
-	style, err := f.NewStyle(&excelize.Style{Alignment: &alignment})
-	err = f.SetCellStyle(sheet, "C"+rowIndex, "C"+rowIndex, style)
+	firstCol, err := excelize.ColumnNumberToName(context.FirstDateColumnIndex)
 	if err != nil {
 		return err
 	}
 
-	err = f.SetCellValue(sheet, "C"+rowIndex, issue.Key+": "+issue.Summary)
+	lastCol, err := excelize.ColumnNumberToName(context.ColsCount)
 	if err != nil {
 		return err
 	}
 
-	style, err = f.NewStyle(&excelize.Style{NumFmt: 177})
-	err = f.SetCellStyle(sheet, "D"+rowIndex, "D"+rowIndex, style)
+	costFormula, err := s.getCostFormula(issueRow, context)
 	if err != nil {
 		return err
 	}
 
-	err = f.SetCellValue(sheet, "D"+rowIndex, rate)
-	if err != nil {
-		return err
+	task := issue.Key + ": " + issue.Summary
+	if len(issue.WorkType) > 0 {
+		task += " (" + issue.WorkType + ")"
 	}
-
-	// hours
-	firstCol, err := excelize.ColumnNumberToName(context.FirstDateColumnIndex)
-	if err != nil {
-		return err
+	if issue.Unapproved {
+		task += " " + ReportUnapprovedLabel
 	}
 
-	lastCol, err := excelize.ColumnNumberToName(context.ColsCount)
-	if err != nil {
-		return err
+	row := make([]interface{}, context.ColsCount)
+	s.setColumnCell(row, constants.ColumnTask, excelize.Cell{StyleID: context.Styles.IssueTask, Value: task}, context)
+	s.setColumnCell(row, constants.ColumnRate, excelize.Cell{StyleID: context.Styles.IssueRate, Value: issueRow.Rate}, context)
+	for _, field := range s.layoutConfig.IssueFields {
+		s.setColumnCell(row, constants.ColumnIssueFieldPrefix+field.Name, excelize.Cell{StyleID: context.Styles.IssueTask, Value: issue.Fields[field.Name]}, context)
 	}
 
+	// hours
 	formula := "sum(" + firstCol + rowIndex + ":" + lastCol + rowIndex + ")"
-
-	err = f.SetCellFormula(sheet, "E"+rowIndex, formula)
-	if err != nil {
-		return err
-	}
+	formula = s.getRoundingFormula(formula, issueRow.Rounding, constants.RoundingScopeIssue)
+	s.setColumnCell(row, constants.ColumnHours, s.getFormulaCell(0, formula, issueRow.Hours), context)
 
 	// total cost
-	style, err = f.NewStyle(&excelize.Style{NumFmt: 177})
-	err = f.SetCellStyle(sheet, "F"+rowIndex, "F"+rowIndex, style)
-	if err != nil {
-		return err
+	s.setColumnCell(row, constants.ColumnCost, s.getFormulaCell(context.Styles.IssueCost, costFormula, issueRow.Cost), context)
+
+	for column, periodHours := range issueRow.ColumnToHours {
+		row[column-1] = excelize.Cell{StyleID: context.Styles.IssuePeriod, Value: periodHours}
 	}
 
-	err = f.SetCellFormula(sheet, "F"+rowIndex, "D"+rowIndex+"*"+"E"+rowIndex)
+	err = s.fillBillingCells(row, issueRow, 0, context.Styles.IssueCost, context)
 	if err != nil {
 		return err
 	}
 
-	for _, effort := range issue.Efforts {
-		date, err := time.Parse(constants.InputDateFormat, effort.Date)
-		if err != nil {
-			return err
-		}
+	s.applyWeekendStyle(row, issueRow.WeekendPeriods, context)
 
-		formattedDate := fmt.Sprintf(ColumnReportDateFormat, date.Month(), date.Day())
+	return s.writeRow(sw, row, excelize.RowOpts{}, context)
+}
 
-		for i := 7; i <= context.ColsCount; i++ {
-			col, err := excelize.ColumnNumberToName(i)
-			if err != nil {
-				return err
-			}
+// getRoundingFormula is the same as getRoundedHours for the hours formula.
+func (s *ExcelService) getRoundingFormula(formula string, rounding models.RoundingAppConfig, scope string) string {
+	if rounding.Scope != scope || rounding.Minutes <= 0 {
+		return formula
+	}
 
-			value, err := f.GetCellValue(sheet, col+"1")
-			if err != nil {
-				return err
-			}
+	increment := strconv.Itoa(rounding.Minutes) + "/60"
 
-			if value == formattedDate {
-				err = f.SetCellValue(sheet, col+rowIndex, s.convertSecondsToHours(effort.TimeSpentSeconds))
-				if err != nil {
-					return err
-				}
-				break
-			}
-		}
+	switch rounding.Mode {
+	case constants.RoundingModeNearest:
+		return "mround(" + formula + "," + increment + ")"
+	case constants.RoundingModeUp:
+		return "ceiling(" + formula + "," + increment + ")"
+	default:
+		return formula
 	}
-
-	return nil
 }
 
-func (s *ExcelService) convertSecondsToHours(seconds int) float64 {
-	value := float64(seconds) / 3600
+// getFormulaCell puts the value calculated from worklog as cached result of formula,
+// so viewers which don't recalculate formulas show the value as well.
+func (s *ExcelService) getFormulaCell(style int, formula string, value float64) excelize.Cell {
+	if s.reportConfig.ValuesOnly {
+		return excelize.Cell{StyleID: style, Value: value}
+	}
 
-	return math.Round(value*100) / 100
+	return excelize.Cell{StyleID: style, Formula: formula, Value: value}
 }
 
-func (s *ExcelService) fillTotalRow(f *excelize.File, sheet string, worklog *models.Worklog, context *models.ExcelContext) error {
-	context.LastRowIndex++
-	rowIndex := strconv.Itoa(context.LastRowIndex)
-
-	alignment := excelize.Alignment{Horizontal: "center", Vertical: "center"}
-	font := excelize.Font{Size: 12, Color: "#000000", Bold: true}
-	fill := excelize.Fill{Color: []string{"#53aede"}, Type: "pattern", Pattern: 3}
-	style, err := f.NewStyle(&excelize.Style{Alignment: &alignment, Font: &font, Fill: fill})
-	err = f.SetRowStyle(sheet, context.LastRowIndex, context.LastRowIndex, style)
+// setCellFormulaValue is the same as getFormulaCell for sheets written without stream writer.
+func (s *ExcelService) setCellFormulaValue(f *excelize.File, sheet, cell, formula string, value float64) error {
+	err := f.SetCellValue(sheet, cell, value)
 	if err != nil {
 		return err
 	}
 
-	err = f.SetCellValue(sheet, "A"+rowIndex, "Total")
-	if err != nil {
-		return err
+	if s.reportConfig.ValuesOnly {
+		return nil
 	}
 
-	totalHoursFormula := ""
-	totalCostFormula := ""
-
-	for i := 2; i < context.LastRowIndex; i++ {
-		isUserRow, err := s.isUserRow(f, sheet, worklog, i)
-		if err != nil {
-			return err
-		}
-
-		if *isUserRow {
-			separator := ""
-			if len(totalHoursFormula) > 0 {
-				separator = ","
-			}
-
-			totalHoursFormula += separator + "E" + strconv.Itoa(i)
-			totalCostFormula += separator + "F" + strconv.Itoa(i)
-		}
-	}
+	return f.SetCellFormula(sheet, cell, formula)
+}
 
-	style, err = f.NewStyle(&excelize.Style{Alignment: &alignment, Font: &font, Fill: fill})
-	err = f.SetCellStyle(sheet, "E"+rowIndex, "E"+rowIndex, style)
+func (s *ExcelService) fillTotalRow(sw *excelize.StreamWriter, totalRow models.ExcelRow, context *models.ExcelContext) error {
+	hoursCol, err := excelize.ColumnNumberToName(context.ColumnToIndex[constants.ColumnHours])
 	if err != nil {
 		return err
 	}
 
-	err = f.SetCellFormula(sheet, "E"+rowIndex, "sum("+totalHoursFormula+")")
+	costCol, err := excelize.ColumnNumberToName(context.ColumnToIndex[constants.ColumnCost])
 	if err != nil {
 		return err
 	}
 
-	style, err = f.NewStyle(&excelize.Style{Alignment: &alignment, Font: &font, Fill: fill, NumFmt: 177})
-	err = f.SetCellStyle(sheet, "F"+rowIndex, "F"+rowIndex, style)
-	if err != nil {
-		return err
+	row := make([]interface{}, context.ColsCount)
+	row[context.FirstColumnIndex-1] = excelize.Cell{StyleID: context.Styles.Total, Value: "Total"}
+	s.setColumnCell(row, constants.ColumnHours, s.getFormulaCell(context.Styles.Total, s.getSumFormula(hoursCol, totalRow.Children), totalRow.Hours), context)
+	s.setColumnCell(row, constants.ColumnCost, s.getFormulaCell(context.Styles.TotalCost, s.getSumFormula(costCol, totalRow.Children), totalRow.Cost), context)
+
+	for column, hours := range totalRow.ColumnToHours {
+		col, err := excelize.ColumnNumberToName(column)
+		if err != nil {
+			return err
+		}
+
+		row[column-1] = s.getFormulaCell(context.Styles.Total, s.getSumFormula(col, totalRow.Children), hours)
 	}
 
-	err = f.SetCellFormula(sheet, "F"+rowIndex, "sum("+totalCostFormula+")")
+	err = s.fillBillingCells(row, totalRow, context.Styles.Total, context.Styles.TotalCost, context)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return s.writeRow(sw, row, excelize.RowOpts{StyleID: context.Styles.Total}, context)
 }
 
-func (s *ExcelService) isUserRow(f *excelize.File, sheet string, worklog *models.Worklog, rowIndex int) (*bool, error) {
-	userNameCandidate, err := f.GetCellValue(sheet, "A"+strconv.Itoa(rowIndex))
-	if err != nil {
-		return nil, err
-	}
-
-	falseResult := false
-	trueResult := true
-
-	if len(userNameCandidate) == 0 {
-		return &falseResult, nil
-	}
+// applyWeekendStyle highlights weekend period cells of the row, cells of weeks and months are highlighted
+// if the row has hours logged on weekends in them.
+func (s *ExcelService) applyWeekendStyle(row []interface{}, weekendPeriods map[int]bool, context *models.ExcelContext) {
+	for i, period := range context.Periods {
+		if !period.IsWeekend && !weekendPeriods[i] {
+			continue
+		}
 
-	for _, project := range worklog.Projects {
-		if userNameCandidate == project.Key {
-			return &falseResult, nil
+		column := context.FirstDateColumnIndex + i - 1
+		if cell, ok := row[column].(excelize.Cell); ok {
+			cell.StyleID = context.Styles.Weekend
+			row[column] = cell
+		} else {
+			row[column] = excelize.Cell{StyleID: context.Styles.Weekend}
 		}
 	}
-
-	return &trueResult, nil
 }
 
-func (s *ExcelService) finalize(f *excelize.File, sheet, dateFrom, dateTo string, context *models.ExcelContext) error {
-	// dates
-	startDate, err := time.Parse(constants.InputDateFormat, dateFrom)
-	if err != nil {
-		return err
-	}
-
-	endDate, err := time.Parse(constants.InputDateFormat, dateTo)
-	if err != nil {
-		return err
-	}
-
-	borders := []excelize.Border{
-		{Type: "top", Color: "#000000", Style: 1},
-		{Type: "left", Color: "#000000", Style: 1},
-		{Type: "bottom", Color: "#000000", Style: 1},
-		{Type: "right", Color: "#000000", Style: 1},
-	}
-	alignment := excelize.Alignment{Horizontal: "center", Vertical: "center"}
-	font := excelize.Font{Size: 12, Color: "#000000", Bold: true}
-	fill := excelize.Fill{Color: []string{"#FEC7CE"}, Type: "pattern", Pattern: 3}
-	headerStyle, err := f.NewStyle(&excelize.Style{Border: borders, Alignment: &alignment, Font: &font, Fill: fill})
-	if err != nil {
-		return err
+// finalize highlights zero rates of users, it must be called before flushing the stream writer.
+func (s *ExcelService) finalize(f *excelize.File, context *models.ExcelContext) error {
+	userRows := s.getRowsByRole(context, constants.RowRoleUser)
+	if _, ok := context.ColumnToIndex[constants.ColumnRate]; !ok || len(userRows) == 0 {
+		return nil
 	}
 
-	bodyStyle, err := f.NewStyle(&excelize.Style{Fill: fill})
+	conditionalFormat, err := s.getConditionalFormat(f)
 	if err != nil {
 		return err
 	}
 
-	i := 6
-	for date := startDate; !date.After(endDate); date = date.AddDate(0, 0, 1) {
-		i++
-		weekday := date.Weekday()
-
-		if weekday == 0 || weekday == 6 {
-			headerCell, err := excelize.CoordinatesToCellName(i, 1)
-			if err != nil {
-				return err
-			}
-
-			err = f.SetCellStyle(sheet, headerCell, headerCell, headerStyle)
-			if err != nil {
-				return err
-			}
-
-			bodyTopCell, err := excelize.CoordinatesToCellName(i, 2)
-			if err != nil {
-				return err
-			}
-
-			bodyBottomCell, err := excelize.CoordinatesToCellName(i, context.LastRowIndex-1)
-			if err != nil {
-				return err
-			}
-
-			err = f.SetCellStyle(sheet, bodyTopCell, bodyBottomCell, bodyStyle)
-			if err != nil {
-				return err
-			}
+	var cells []string
+	for _, userRow := range userRows {
+		cell, err := s.getColumnCellName(constants.ColumnRate, userRow.Index, context)
+		if err != nil {
+			return err
 		}
+		cells = append(cells, cell)
 	}
 
-	return nil
+	return f.SetConditionalFormat(context.Sheet, strings.Join(cells, " "), conditionalFormat)
 }
@@ -0,0 +1,269 @@
+package services
+
+import (
+	"github.com/xuri/excelize/v2"
+	"sort"
+	"strconv"
+	"strings"
+	"tempo-worklog/constants"
+	"tempo-worklog/models"
+)
+
+const (
+	ChartsSheetName = "Charts"
+)
+
+// createChartsSheet puts chart data tables on the sheet and binds charts to them.
+// Data tables consist of formulas referencing the detail sheets, so charts follow edited rates.
+func (s *ExcelService) createChartsSheet(f *excelize.File, sheet string, contexts []*models.ExcelContext) error {
+	_, err := f.NewSheet(sheet)
+	if err != nil {
+		return err
+	}
+
+	font := excelize.Font{Family: s.layoutConfig.Theme.FontFamily, Size: s.layoutConfig.Theme.FontSize, Color: s.layoutConfig.Theme.FontColor, Bold: true}
+	style, err := f.NewStyle(&excelize.Style{Font: &font})
+	if err != nil {
+		return err
+	}
+
+	err = f.SetRowStyle(sheet, 1, 1, style)
+	if err != nil {
+		return err
+	}
+
+	usersCount, err := s.fillUserHoursChartData(f, sheet, contexts)
+	if err != nil {
+		return err
+	}
+
+	projectsCount, err := s.fillProjectCostChartData(f, sheet, contexts)
+	if err != nil {
+		return err
+	}
+
+	periodsCount, err := s.fillPeriodHoursChartData(f, sheet, contexts)
+	if err != nil {
+		return err
+	}
+
+	sheetRef := s.getSheetReference(sheet)
+
+	err = f.AddChart(sheet, "J2", &excelize.Chart{
+		Type:   "bar",
+		Title:  excelize.ChartTitle{Name: "Hours per user"},
+		Legend: excelize.ChartLegend{Position: "none"},
+		Series: []excelize.ChartSeries{{
+			Name:       sheetRef + "$B$1",
+			Categories: sheetRef + "$A$2:$A$" + strconv.Itoa(usersCount+1),
+			Values:     sheetRef + "$B$2:$B$" + strconv.Itoa(usersCount+1),
+		}},
+		YAxis: excelize.ChartAxis{MajorGridLines: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = f.AddChart(sheet, "J18", &excelize.Chart{
+		Type:   "col",
+		Title:  excelize.ChartTitle{Name: "Cost per project"},
+		Legend: excelize.ChartLegend{Position: "none"},
+		Series: []excelize.ChartSeries{{
+			Name:       sheetRef + "$E$1",
+			Categories: sheetRef + "$D$2:$D$" + strconv.Itoa(projectsCount+1),
+			Values:     sheetRef + "$E$2:$E$" + strconv.Itoa(projectsCount+1),
+		}},
+		YAxis: excelize.ChartAxis{MajorGridLines: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	err = f.AddChart(sheet, "J34", &excelize.Chart{
+		Type:   "line",
+		Title:  excelize.ChartTitle{Name: "Hours trend"},
+		Legend: excelize.ChartLegend{Position: "none"},
+		Series: []excelize.ChartSeries{{
+			Name:       sheetRef + "$H$1",
+			Categories: sheetRef + "$G$2:$G$" + strconv.Itoa(periodsCount+1),
+			Values:     sheetRef + "$H$2:$H$" + strconv.Itoa(periodsCount+1),
+		}},
+		YAxis: excelize.ChartAxis{MajorGridLines: true},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (s *ExcelService) fillUserHoursChartData(f *excelize.File, sheet string, contexts []*models.ExcelContext) (int, error) {
+	accountIdToName := map[string]string{}
+	accountIdToHours := map[string]float64{}
+	accountIdToCells := map[string][]string{} // user hours cells in all projects
+
+	for _, context := range contexts {
+		for _, userRow := range s.getRowsByRole(context, constants.RowRoleUser) {
+			cell, err := s.getColumnCellName(constants.ColumnHours, userRow.Index, context)
+			if err != nil {
+				return 0, err
+			}
+
+			accountId := userRow.User.AccountId
+			accountIdToName[accountId] = userRow.User.DisplayName
+			accountIdToHours[accountId] += userRow.Hours
+			accountIdToCells[accountId] = append(accountIdToCells[accountId], s.getSheetReference(context.Sheet)+cell)
+		}
+	}
+
+	accountIds := make([]string, 0, len(accountIdToName))
+	for accountId := range accountIdToName {
+		accountIds = append(accountIds, accountId)
+	}
+	sort.Slice(accountIds, func(i, j int) bool {
+		return strings.ToLower(accountIdToName[accountIds[i]]) < strings.ToLower(accountIdToName[accountIds[j]])
+	})
+
+	header := []interface{}{"Name", "Hours"}
+	err := f.SetSheetRow(sheet, "A1", &header)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, accountId := range accountIds {
+		row := strconv.Itoa(i + 2)
+
+		err = f.SetCellValue(sheet, "A"+row, accountIdToName[accountId])
+		if err != nil {
+			return 0, err
+		}
+
+		formula := "sum(" + strings.Join(accountIdToCells[accountId], ",") + ")"
+
+		err = s.setCellFormulaValue(f, sheet, "B"+row, formula, s.pricingService.roundHours(accountIdToHours[accountId]))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = f.SetColWidth(sheet, "A", "A", 30)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(accountIds), nil
+}
+
+func (s *ExcelService) fillProjectCostChartData(f *excelize.File, sheet string, contexts []*models.ExcelContext) (int, error) {
+	var projectKeys []string
+	projectKeyToCost := map[string]float64{}
+	projectKeyToCells := map[string][]string{} // user cost cells of project
+
+	for _, context := range contexts {
+		for _, userRow := range s.getRowsByRole(context, constants.RowRoleUser) {
+			cell, err := s.getColumnCellName(constants.ColumnCost, userRow.Index, context)
+			if err != nil {
+				return 0, err
+			}
+
+			projectKey := userRow.ProjectKey
+			if _, ok := projectKeyToCells[projectKey]; !ok {
+				projectKeys = append(projectKeys, projectKey)
+			}
+			projectKeyToCost[projectKey] += userRow.Cost
+			projectKeyToCells[projectKey] = append(projectKeyToCells[projectKey], s.getSheetReference(context.Sheet)+cell)
+		}
+	}
+
+	header := []interface{}{"Project", "Total cost"}
+	err := f.SetSheetRow(sheet, "D1", &header)
+	if err != nil {
+		return 0, err
+	}
+
+	style, err := f.NewStyle(&excelize.Style{NumFmt: 177})
+	if err != nil {
+		return 0, err
+	}
+
+	for i, projectKey := range projectKeys {
+		row := strconv.Itoa(i + 2)
+
+		err = f.SetCellValue(sheet, "D"+row, projectKey)
+		if err != nil {
+			return 0, err
+		}
+
+		err = f.SetCellStyle(sheet, "E"+row, "E"+row, style)
+		if err != nil {
+			return 0, err
+		}
+
+		formula := "sum(" + strings.Join(projectKeyToCells[projectKey], ",") + ")"
+
+		err = s.setCellFormulaValue(f, sheet, "E"+row, formula, projectKeyToCost[projectKey])
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = f.SetColWidth(sheet, "D", "E", 15)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(projectKeys), nil
+}
+
+// fillPeriodHoursChartData sums period columns of total rows of the detail sheets.
+func (s *ExcelService) fillPeriodHoursChartData(f *excelize.File, sheet string, contexts []*models.ExcelContext) (int, error) {
+	if len(contexts) == 0 {
+		return 0, nil
+	}
+
+	header := []interface{}{"Period", "Hours"}
+	err := f.SetSheetRow(sheet, "G1", &header)
+	if err != nil {
+		return 0, err
+	}
+
+	periods := contexts[0].Periods
+
+	for i, period := range periods {
+		row := strconv.Itoa(i + 2)
+
+		err = f.SetCellValue(sheet, "G"+row, period.Label)
+		if err != nil {
+			return 0, err
+		}
+
+		var sums []string
+		hours := 0.0
+
+		for _, context := range contexts {
+			column := context.FirstDateColumnIndex + i
+
+			col, err := excelize.ColumnNumberToName(column)
+			if err != nil {
+				return 0, err
+			}
+
+			for _, totalRow := range s.getRowsByRole(context, constants.RowRoleTotal) {
+				hours += totalRow.ColumnToHours[column]
+				sums = append(sums, s.getSheetReference(context.Sheet)+col+strconv.Itoa(totalRow.Index))
+			}
+		}
+
+		err = s.setCellFormulaValue(f, sheet, "H"+row, "sum("+strings.Join(sums, ",")+")", s.pricingService.roundHours(hours))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = f.SetColWidth(sheet, "G", "G", s.getPeriodColumnWidth()+4)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(periods), nil
+}
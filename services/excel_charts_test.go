@@ -0,0 +1,63 @@
+package services
+
+import (
+	"strings"
+	"tempo-worklog/constants"
+	"tempo-worklog/models"
+	"testing"
+)
+
+func TestSaveCharts(t *testing.T) {
+	tests := []struct {
+		name       string
+		layout     string
+		wantSheets int // detail sheets referenced by hours of user
+	}{
+		{name: "single sheet", layout: constants.LayoutSingle, wantSheets: 1},
+		{name: "sheet per project", layout: constants.LayoutPerProject, wantSheets: 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reportConfig := models.ReportAppConfig{Layout: test.layout, Charts: true}
+			f := saveTestReport(t, reportConfig, newTestWorklog("PRJ", "OTH"))
+
+			if name := getCell(t, f, ChartsSheetName, "A2"); name != "John Smith" {
+				t.Errorf("user of chart data is %q, want %q", name, "John Smith")
+			}
+
+			formula, err := f.GetCellFormula(ChartsSheetName, "B2")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cells := strings.Count(formula, ",") + 1; cells != 2 {
+				t.Errorf("hours of user sum %d cells, want 2: %s", cells, formula)
+			}
+
+			sheets := map[string]bool{}
+			for _, cell := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(formula, "sum("), ")"), ",") {
+				sheets[cell[:strings.LastIndex(cell, "!")]] = true
+			}
+			if len(sheets) != test.wantSheets {
+				t.Errorf("hours of user reference %d sheets, want %d: %s", len(sheets), test.wantSheets, formula)
+			}
+
+			for cell, want := range map[string]string{"D2": "PRJ", "D3": "OTH", "G2": "1/2", "G8": "1/8"} {
+				if value := getCell(t, f, ChartsSheetName, cell); value != want {
+					t.Errorf("%s of chart data is %q, want %q", cell, value, want)
+				}
+			}
+
+			charts := 0
+			f.Pkg.Range(func(key, value interface{}) bool {
+				if strings.HasPrefix(key.(string), "xl/charts/chart") {
+					charts++
+				}
+				return true
+			})
+			if charts != 3 {
+				t.Errorf("report has %d charts, want 3", charts)
+			}
+		})
+	}
+}
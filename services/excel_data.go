@@ -0,0 +1,156 @@
+package services
+
+import (
+	"github.com/xuri/excelize/v2"
+	"strconv"
+	"tempo-worklog/constants"
+	"tempo-worklog/models"
+	"time"
+)
+
+const (
+	DataSheetName  = "Data"
+	DataTableName  = "WorklogData"
+	PivotSheetName = "Pivot"
+)
+
+// createDataSheet puts one row per effort to the sheet formatted as Excel table,
+// which can be used as source for pivot tables and filters.
+func (s *ExcelService) createDataSheet(f *excelize.File, sheet string, worklog *models.Worklog) (int, error) {
+	_, err := f.NewSheet(sheet)
+	if err != nil {
+		return 0, err
+	}
+
+	header := []interface{}{"Project", "User", "Position", "Issue", "Summary", "Date", "Hours", "Unrounded hours", "Rate", "Cost",
+		"Logged hours", "Billable hours", "Billable cost"}
+	err = f.SetSheetRow(sheet, "A1", &header)
+	if err != nil {
+		return 0, err
+	}
+
+	rowIndex := 1
+
+	for _, project := range worklog.Projects {
+		for _, user := range project.Users {
+			for _, issue := range user.Issues {
+				for _, effort := range issue.Efforts {
+					date, err := time.Parse(constants.InputDateFormat, effort.Date)
+					if err != nil {
+						return 0, err
+					}
+
+					hours := s.pricingService.convertSecondsToHours(effort.TimeSpentSeconds)
+					billableHours := s.pricingService.convertSecondsToHours(effort.BillableSeconds)
+					rate := s.pricingService.getIssueRate(user, issue)
+
+					rowIndex++
+					values := []interface{}{
+						project.Key,
+						user.DisplayName,
+						user.Position,
+						issue.Key,
+						issue.Summary,
+						date,
+						hours,
+						s.pricingService.convertSecondsToHours(effort.UnroundedSeconds),
+						rate,
+						hours * float64(rate),
+						s.pricingService.convertSecondsToHours(effort.LoggedSeconds),
+						billableHours,
+						billableHours * float64(rate),
+					}
+					err = f.SetSheetRow(sheet, "A"+strconv.Itoa(rowIndex), &values)
+					if err != nil {
+						return 0, err
+					}
+				}
+			}
+		}
+	}
+
+	lastRow := strconv.Itoa(rowIndex)
+
+	dateStyle, err := f.NewStyle(&excelize.Style{NumFmt: 14})
+	if err != nil {
+		return 0, err
+	}
+
+	err = f.SetCellStyle(sheet, "F2", "F"+lastRow, dateStyle)
+	if err != nil {
+		return 0, err
+	}
+
+	costStyle, err := f.NewStyle(&excelize.Style{NumFmt: 177})
+	if err != nil {
+		return 0, err
+	}
+
+	err = f.SetCellStyle(sheet, "I2", "J"+lastRow, costStyle)
+	if err != nil {
+		return 0, err
+	}
+
+	err = f.SetCellStyle(sheet, "M2", "M"+lastRow, costStyle)
+	if err != nil {
+		return 0, err
+	}
+
+	widths := []float64{15, 30, 30, 15, 60, 12, 10, 18, 10, 15, 15, 15, 15}
+	for i, width := range widths {
+		col, err := excelize.ColumnNumberToName(i + 1)
+		if err != nil {
+			return 0, err
+		}
+
+		err = f.SetColWidth(sheet, col, col, width)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	showRowStripes := true
+	err = f.AddTable(sheet, "A1:M"+lastRow, &excelize.TableOptions{
+		Name:           DataTableName,
+		StyleName:      "TableStyleMedium2",
+		ShowRowStripes: &showRowStripes,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return rowIndex, nil
+}
+
+func (s *ExcelService) createPivotSheet(f *excelize.File, sheet, dataSheet string, dataLastRowIndex int) error {
+	_, err := f.NewSheet(sheet)
+	if err != nil {
+		return err
+	}
+
+	err = f.AddPivotTable(&excelize.PivotTableOptions{
+		DataRange:       dataSheet + "!$A$1:$M$" + strconv.Itoa(dataLastRowIndex),
+		PivotTableRange: sheet + "!$A$3:$D$20",
+		Rows: []excelize.PivotTableField{
+			{Data: "Project", DefaultSubtotal: true},
+			{Data: "Position", DefaultSubtotal: true},
+			{Data: "User"},
+		},
+		Data: []excelize.PivotTableField{
+			{Data: "Hours", Name: "Sum of hours", Subtotal: "Sum"},
+			{Data: "Cost", Name: "Sum of cost", Subtotal: "Sum"},
+		},
+		RowGrandTotals:      true,
+		ColGrandTotals:      true,
+		ShowDrill:           true,
+		ShowRowHeaders:      true,
+		ShowColHeaders:      true,
+		ShowLastColumn:      true,
+		PivotTableStyleName: "PivotStyleLight16",
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
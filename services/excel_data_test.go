@@ -0,0 +1,72 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"tempo-worklog/models"
+	"testing"
+)
+
+func TestSaveDataSheet(t *testing.T) {
+	tests := []struct {
+		name       string
+		dataSheet  bool
+		pivotTable bool
+		wantSheets map[string]bool
+	}{
+		{name: "no data", wantSheets: map[string]bool{DataSheetName: false, PivotSheetName: false}},
+		{name: "pivot without data", pivotTable: true, wantSheets: map[string]bool{DataSheetName: false, PivotSheetName: false}},
+		{name: "data", dataSheet: true, wantSheets: map[string]bool{DataSheetName: true, PivotSheetName: false}},
+		{name: "data with pivot", dataSheet: true, pivotTable: true, wantSheets: map[string]bool{DataSheetName: true, PivotSheetName: true}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reportConfig := models.ReportAppConfig{DataSheet: test.dataSheet, PivotTable: test.pivotTable}
+			f := saveTestReport(t, reportConfig, newTestWorklog("PRJ", "OTH"))
+
+			sheets := map[string]bool{}
+			for _, sheet := range f.GetSheetList() {
+				sheets[sheet] = true
+			}
+			for sheet, want := range test.wantSheets {
+				if sheets[sheet] != want {
+					t.Errorf("sheet %s exists: %v, want %v", sheet, sheets[sheet], want)
+				}
+			}
+
+			parts := map[string]bool{}
+			f.Pkg.Range(func(key, value interface{}) bool {
+				for _, prefix := range []string{"xl/tables/", "xl/pivotTables/"} {
+					if strings.HasPrefix(key.(string), prefix) {
+						parts[prefix] = true
+					}
+				}
+				return true
+			})
+			if parts["xl/tables/"] != test.dataSheet {
+				t.Errorf("data table exists: %v, want %v", parts["xl/tables/"], test.dataSheet)
+			}
+			if parts["xl/pivotTables/"] != test.wantSheets[PivotSheetName] {
+				t.Errorf("pivot table exists: %v, want %v", parts["xl/pivotTables/"], test.wantSheets[PivotSheetName])
+			}
+
+			if !test.dataSheet {
+				return
+			}
+
+			rows, err := f.GetRows(DataSheetName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rows) != 3 {
+				t.Fatalf("data sheet has %d rows, want header and 2 efforts", len(rows))
+			}
+
+			want := []string{"OTH", "John Smith", "", "OTH-1", "Task", "01-02-23", "1", "1", "10", "10", "1", "1", "10"}
+			if !reflect.DeepEqual(rows[2], want) {
+				t.Errorf("data row is %q, want %q", rows[2], want)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package services
+
+import (
+	"github.com/xuri/excelize/v2"
+	"strconv"
+	"tempo-worklog/models"
+)
+
+const (
+	PlanSheetName = "Plan"
+)
+
+func (s *ExcelService) preparePlan(f *excelize.File, sheet string) error {
+	_, err := f.NewSheet(sheet)
+	if err != nil {
+		return err
+	}
+
+	alignment := excelize.Alignment{Horizontal: "center", Vertical: "center"}
+	borders := []excelize.Border{
+		{Type: "top", Color: "#000000", Style: 1},
+		{Type: "left", Color: "#000000", Style: 1},
+		{Type: "bottom", Color: "#000000", Style: 1},
+		{Type: "right", Color: "#000000", Style: 1},
+	}
+	font := excelize.Font{Family: s.layoutConfig.Theme.FontFamily, Size: s.layoutConfig.Theme.HeaderFontSize, Color: s.layoutConfig.Theme.HeaderFontColor, Bold: true}
+	fill := s.getThemeFill(s.layoutConfig.Theme.HeaderColor)
+	style, err := f.NewStyle(&excelize.Style{Alignment: &alignment, Font: &font, Border: borders, Fill: fill})
+	if err != nil {
+		return err
+	}
+
+	err = f.SetCellStyle(sheet, "A1", "F1", style)
+	if err != nil {
+		return err
+	}
+
+	err = f.SetRowHeight(sheet, 1, 25)
+	if err != nil {
+		return err
+	}
+
+	header := []interface{}{"Project", "Name", "Planned hours", "Actual hours", "Variance", "Variance %"}
+	err = f.SetSheetRow(sheet, "A1", &header)
+	if err != nil {
+		return err
+	}
+
+	widths := []float64{20, 30, 15, 15, 15, 15}
+	for i, width := range widths {
+		col, err := excelize.ColumnNumberToName(i + 1)
+		if err != nil {
+			return err
+		}
+
+		err = f.SetColWidth(sheet, col, col, width)
+		if err != nil {
+			return err
+		}
+	}
+
+	return f.SetPanes(sheet, &excelize.Panes{Freeze: true, YSplit: 1})
+}
+
+// fillPlan puts planned and actual hours of projects and their users, users planned but without worklog
+// go after users with worklog, variance over the threshold is highlighted.
+func (s *ExcelService) fillPlan(f *excelize.File, sheet string, worklog *models.Worklog, dateToPeriod map[string]int) error {
+	theme := s.layoutConfig.Theme
+	font := excelize.Font{Family: theme.FontFamily, Size: theme.FontSize, Color: theme.FontColor, Bold: true}
+
+	projectStyles, err := s.getSummaryRowStyles(f, &font, theme.ProjectColor)
+	if err != nil {
+		return err
+	}
+
+	userStyles, err := s.getSummaryRowStyles(f, nil, "")
+	if err != nil {
+		return err
+	}
+
+	totalStyles, err := s.getSummaryRowStyles(f, &font, theme.TotalColor)
+	if err != nil {
+		return err
+	}
+
+	accountIdToName := map[string]string{}
+	for _, account := range s.getAccounts(worklog) {
+		accountIdToName[account.AccountId] = account.DisplayName
+	}
+
+	rowIndex := 1
+	totalPlanned := 0.0
+	totalActual := 0.0
+
+	for _, project := range worklog.Projects {
+		accountIdToPlanned := s.getAccountIdToPlanned(project)
+
+		var accountIds []string
+		accountIdToActual := map[string]float64{}
+		for _, user := range project.Users {
+			actual, _, err := s.getUsersHoursAndCost([]models.User{user}, project.Rounding, dateToPeriod)
+			if err != nil {
+				return err
+			}
+			accountIdToActual[user.AccountId] = actual
+			accountIds = append(accountIds, user.AccountId)
+		}
+		for _, plan := range project.Plans {
+			if _, ok := accountIdToActual[plan.AccountId]; !ok {
+				accountIds = append(accountIds, plan.AccountId)
+				accountIdToName[plan.AccountId] = plan.DisplayName
+			}
+		}
+
+		projectPlanned := 0.0
+		projectActual := 0.0
+		for _, accountId := range accountIds {
+			projectPlanned = s.pricingService.roundHours(projectPlanned + accountIdToPlanned[accountId])
+			projectActual = s.pricingService.roundHours(projectActual + accountIdToActual[accountId])
+		}
+		totalPlanned = s.pricingService.roundHours(totalPlanned + projectPlanned)
+		totalActual = s.pricingService.roundHours(totalActual + projectActual)
+
+		rowIndex++
+		err = s.fillPlanRow(f, sheet, rowIndex, project.Key, "", projectPlanned, projectActual, projectStyles)
+		if err != nil {
+			return err
+		}
+
+		for _, accountId := range accountIds {
+			name, ok := accountIdToName[accountId]
+			if !ok {
+				name = accountId
+			}
+
+			rowIndex++
+			err = s.fillPlanRow(f, sheet, rowIndex, "", name, accountIdToPlanned[accountId], accountIdToActual[accountId], userStyles)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	rowIndex++
+	err = s.fillPlanRow(f, sheet, rowIndex, "Total", "", totalPlanned, totalActual, totalStyles)
+	if err != nil {
+		return err
+	}
+
+	return s.setVarianceFormat(f, sheet, "F2:F"+strconv.Itoa(rowIndex))
+}
+
+// getAccountIdToPlanned sums up planned hours of users in the project.
+func (s *ExcelService) getAccountIdToPlanned(project models.Project) map[string]float64 {
+	accountIdToPlanned := map[string]float64{}
+	for _, plan := range project.Plans {
+		accountIdToPlanned[plan.AccountId] += s.pricingService.convertSecondsToHours(plan.PlannedSeconds)
+	}
+
+	return accountIdToPlanned
+}
+
+// setVarianceFormat highlights variance percentages of the range which exceed the plan threshold.
+func (s *ExcelService) setVarianceFormat(f *excelize.File, sheet, cellRange string) error {
+	format, err := f.NewConditionalStyle(&excelize.Style{
+		Font: &excelize.Font{Color: "#9A0511"},
+		Fill: excelize.Fill{Type: "pattern", Color: []string{"#FEC7CE"}, Pattern: 1},
+	})
+	if err != nil {
+		return err
+	}
+
+	threshold := strconv.FormatFloat(float64(s.reportConfig.PlanThreshold)/100, 'f', -1, 64)
+	return f.SetConditionalFormat(sheet, cellRange, []excelize.ConditionalFormatOptions{
+		{Type: "cell", Criteria: ">", Format: format, Value: threshold},
+	})
+}
+
+// fillPlanRow puts the row with variance of actual hours from planned ones, variance percentage is empty without plan.
+func (s *ExcelService) fillPlanRow(f *excelize.File, sheet string, rowIndex int, project, user string,
+	planned, actual float64, styles []int) error {
+	row := strconv.Itoa(rowIndex)
+
+	var percentage interface{}
+	if planned > 0 {
+		percentage = (actual - planned) / planned
+	}
+
+	values := []interface{}{project, user, planned, actual, s.pricingService.roundHours(actual - planned), percentage}
+	err := f.SetSheetRow(sheet, "A"+row, &values)
+	if err != nil {
+		return err
+	}
+
+	// column ranges with the same style: text, hours, percentage
+	ranges := [][]string{{"A", "B"}, {"C", "E"}, {"F", "F"}}
+	rangeStyles := []int{styles[0], styles[1], styles[3]}
+	for i, cols := range ranges {
+		err = f.SetCellStyle(sheet, cols[0]+row, cols[1]+row, rangeStyles[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
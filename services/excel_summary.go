@@ -0,0 +1,269 @@
+package services
+
+import (
+	"github.com/xuri/excelize/v2"
+	"sort"
+	"strconv"
+	"tempo-worklog/models"
+)
+
+const (
+	SummarySheetName       = "Summary"
+	SummaryNoPositionLabel = "(no position)"
+)
+
+func (s *ExcelService) prepareSummary(f *excelize.File, sheet string) error {
+	alignment := excelize.Alignment{Horizontal: "center", Vertical: "center"}
+	borders := []excelize.Border{
+		{Type: "top", Color: "#000000", Style: 1},
+		{Type: "left", Color: "#000000", Style: 1},
+		{Type: "bottom", Color: "#000000", Style: 1},
+		{Type: "right", Color: "#000000", Style: 1},
+	}
+	font := excelize.Font{Family: s.layoutConfig.Theme.FontFamily, Size: s.layoutConfig.Theme.HeaderFontSize, Color: s.layoutConfig.Theme.HeaderFontColor, Bold: true}
+	fill := s.getThemeFill(s.layoutConfig.Theme.HeaderColor)
+	style, err := f.NewStyle(&excelize.Style{Alignment: &alignment, Font: &font, Border: borders, Fill: fill})
+	if err != nil {
+		return err
+	}
+
+	header := []interface{}{"Project", "Position", "Name", "Hours", "Total cost", "% of project cost", "Avg rate"}
+	widths := []float64{20, 30, 30, 10, 20, 20, 10}
+
+	// hours of Tempo plans are compared with the actual ones
+	if s.reportConfig.Plans {
+		header = append(header, "Planned hours", "Variance", "Variance %")
+		widths = append(widths, 15, 15, 15)
+	}
+
+	lastCol, err := excelize.ColumnNumberToName(len(header))
+	if err != nil {
+		return err
+	}
+
+	err = f.SetCellStyle(sheet, "A1", lastCol+"1", style)
+	if err != nil {
+		return err
+	}
+
+	err = f.SetRowHeight(sheet, 1, 25)
+	if err != nil {
+		return err
+	}
+
+	err = f.SetSheetRow(sheet, "A1", &header)
+	if err != nil {
+		return err
+	}
+
+	for i, width := range widths {
+		col, err := excelize.ColumnNumberToName(i + 1)
+		if err != nil {
+			return err
+		}
+
+		err = f.SetColWidth(sheet, col, col, width)
+		if err != nil {
+			return err
+		}
+	}
+
+	return f.SetPanes(sheet, &excelize.Panes{Freeze: true, YSplit: 1})
+}
+
+func (s *ExcelService) fillSummary(f *excelize.File, sheet string, worklog *models.Worklog, dateToPeriod map[string]int) error {
+	theme := s.layoutConfig.Theme
+	font := excelize.Font{Family: theme.FontFamily, Size: theme.FontSize, Color: theme.FontColor, Bold: true}
+
+	projectStyles, err := s.getSummaryRowStyles(f, &font, theme.ProjectColor)
+	if err != nil {
+		return err
+	}
+
+	positionStyles, err := s.getSummaryRowStyles(f, &font, theme.UserColor)
+	if err != nil {
+		return err
+	}
+
+	userStyles, err := s.getSummaryRowStyles(f, nil, "")
+	if err != nil {
+		return err
+	}
+
+	totalStyles, err := s.getSummaryRowStyles(f, &font, theme.TotalColor)
+	if err != nil {
+		return err
+	}
+
+	rowIndex := 1
+	totalHours := 0.0
+	totalCost := 0.0
+	totalPlanned := 0.0
+
+	for _, project := range worklog.Projects {
+		projectHours, projectCost, err := s.getUsersHoursAndCost(project.Users, project.Rounding, dateToPeriod)
+		if err != nil {
+			return err
+		}
+		totalHours += projectHours
+		totalCost += projectCost
+
+		// users planned without worklog are counted by the project row only
+		accountIdToPlanned := s.getAccountIdToPlanned(project)
+		projectPlanned := 0.0
+		for _, planned := range accountIdToPlanned {
+			projectPlanned = s.pricingService.roundHours(projectPlanned + planned)
+		}
+		totalPlanned = s.pricingService.roundHours(totalPlanned + projectPlanned)
+
+		rowIndex++
+		err = s.fillSummaryRow(f, sheet, rowIndex, project.Key, "", "", projectHours, projectCost, projectCost, projectPlanned, projectStyles)
+		if err != nil {
+			return err
+		}
+
+		positionToUsers := map[string][]models.User{} // group users by position
+		for _, user := range project.Users {
+			position := user.Position
+			if len(position) == 0 {
+				position = SummaryNoPositionLabel
+			}
+			positionToUsers[position] = append(positionToUsers[position], user)
+		}
+
+		positions := make([]string, 0, len(positionToUsers))
+		for position := range positionToUsers {
+			positions = append(positions, position)
+		}
+		sort.Strings(positions)
+
+		for _, position := range positions {
+			users := positionToUsers[position]
+			positionHours, positionCost, err := s.getUsersHoursAndCost(users, project.Rounding, dateToPeriod)
+			if err != nil {
+				return err
+			}
+
+			positionPlanned := 0.0
+			for _, user := range users {
+				positionPlanned = s.pricingService.roundHours(positionPlanned + accountIdToPlanned[user.AccountId])
+			}
+
+			rowIndex++
+			err = s.fillSummaryRow(f, sheet, rowIndex, "", position, "", positionHours, positionCost, projectCost, positionPlanned, positionStyles)
+			if err != nil {
+				return err
+			}
+
+			for _, user := range users {
+				userHours, userCost, err := s.getUsersHoursAndCost([]models.User{user}, project.Rounding, dateToPeriod)
+				if err != nil {
+					return err
+				}
+
+				rowIndex++
+				err = s.fillSummaryRow(f, sheet, rowIndex, "", "", user.DisplayName, userHours, userCost, projectCost,
+					accountIdToPlanned[user.AccountId], userStyles)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	rowIndex++
+	err = s.fillSummaryRow(f, sheet, rowIndex, "Total", "", "", totalHours, totalCost, totalCost, totalPlanned, totalStyles)
+	if err != nil {
+		return err
+	}
+
+	if !s.reportConfig.Plans {
+		return nil
+	}
+
+	return s.setVarianceFormat(f, sheet, "J2:J"+strconv.Itoa(rowIndex))
+}
+
+func (s *ExcelService) getSummaryRowStyles(f *excelize.File, font *excelize.Font, color string) ([]int, error) {
+	fill := s.getThemeFill(color)
+
+	// text, hours, cost, percentage, rate
+	numFmts := []int{0, 2, 177, 10, 177}
+	styles := make([]int, 0, len(numFmts))
+
+	for _, numFmt := range numFmts {
+		style, err := f.NewStyle(&excelize.Style{Font: font, Fill: fill, NumFmt: numFmt})
+		if err != nil {
+			return nil, err
+		}
+		styles = append(styles, style)
+	}
+
+	return styles, nil
+}
+
+// fillSummaryRow puts hours and cost of the row, planned hours and variance follow them if plans are fetched.
+func (s *ExcelService) fillSummaryRow(f *excelize.File, sheet string, rowIndex int, project, position, user string,
+	hours, cost, projectCost, planned float64, styles []int) error {
+	row := strconv.Itoa(rowIndex)
+
+	percentage := 0.0
+	if projectCost > 0 {
+		percentage = cost / projectCost
+	}
+
+	rate := 0.0
+	if hours > 0 {
+		rate = cost / hours
+	}
+
+	values := []interface{}{project, position, user, hours, cost, percentage, rate}
+
+	// column ranges with the same style: text, hours, cost, percentage, rate
+	ranges := [][]string{{"A", "C"}, {"D", "D"}, {"E", "E"}, {"F", "F"}, {"G", "G"}}
+	rangeStyles := []int{styles[0], styles[1], styles[2], styles[3], styles[4]}
+
+	if s.reportConfig.Plans {
+		var variancePercentage interface{}
+		if planned > 0 {
+			variancePercentage = (hours - planned) / planned
+		}
+
+		values = append(values, planned, s.pricingService.roundHours(hours-planned), variancePercentage)
+		ranges = append(ranges, []string{"H", "I"}, []string{"J", "J"})
+		rangeStyles = append(rangeStyles, styles[1], styles[3])
+	}
+
+	err := f.SetSheetRow(sheet, "A"+row, &values)
+	if err != nil {
+		return err
+	}
+
+	for i, cols := range ranges {
+		err = f.SetCellStyle(sheet, cols[0]+row, cols[1]+row, rangeStyles[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getUsersHoursAndCost sums up hours and cost of the users priced the way the detail sheet does.
+func (s *ExcelService) getUsersHoursAndCost(users []models.User, rounding models.RoundingAppConfig,
+	dateToPeriod map[string]int) (float64, float64, error) {
+	hours := 0.0
+	cost := 0.0
+
+	for _, user := range users {
+		userPrice, err := s.pricingService.getUserPrice(user, rounding, dateToPeriod)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		hours += userPrice.Hours
+		cost += userPrice.Cost
+	}
+
+	return hours, cost, nil
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"errors"
+	"github.com/xuri/excelize/v2"
+	"strconv"
+	"strings"
+	"tempo-worklog/constants"
+	"tempo-worklog/models"
+)
+
+const (
+	TemplateHeaderName = "ReportHeader"
+	TemplateGridName   = "ReportGrid"
+	TemplateTotalsName = "ReportTotals"
+)
+
+// openFile opens the report template if it is configured, otherwise creates an empty workbook.
+func (s *ExcelService) openFile() (*excelize.File, error) {
+	if len(s.templateFilePath) > 0 {
+		return excelize.OpenFile(s.templateFilePath)
+	}
+
+	f := excelize.NewFile()
+
+	if len(s.layoutConfig.Theme.FontFamily) > 0 {
+		err := f.SetDefaultFont(s.layoutConfig.Theme.FontFamily)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return f, nil
+}
+
+// getTemplateAnchor finds the workbook defined name and returns the top left cell of it,
+// nil is returned when the name is not defined.
+func (s *ExcelService) getTemplateAnchor(f *excelize.File, name string) (*models.ExcelAnchor, error) {
+	for _, definedName := range f.GetDefinedName() {
+		if !strings.EqualFold(definedName.Name, name) {
+			continue
+		}
+
+		refersTo := strings.TrimPrefix(definedName.RefersTo, "=")
+		separator := strings.LastIndex(refersTo, "!")
+		if separator < 0 {
+			return nil, errors.New("report template name doesn't refer to a cell: " + name)
+		}
+
+		sheet := refersTo[:separator]
+		if strings.HasPrefix(sheet, "'") && strings.HasSuffix(sheet, "'") {
+			sheet = strings.ReplaceAll(sheet[1:len(sheet)-1], "''", "'")
+		}
+
+		cell := strings.ReplaceAll(strings.Split(refersTo[separator+1:], ":")[0], "$", "")
+
+		columnIndex, rowIndex, err := excelize.CellNameToCoordinates(cell)
+		if err != nil {
+			return nil, err
+		}
+
+		sheetIndex, err := f.GetSheetIndex(sheet)
+		if err != nil {
+			return nil, err
+		}
+
+		if sheetIndex < 0 {
+			return nil, errors.New("report template name refers to unknown sheet: " + name)
+		}
+
+		return &models.ExcelAnchor{Sheet: sheet, ColumnIndex: columnIndex, RowIndex: rowIndex}, nil
+	}
+
+	return nil, nil
+}
+
+// getTemplateRows reads values, styles and merged cells of the first rows of the sheet,
+// the stream writer replaces sheet data, so these rows are written to the report again.
+func (s *ExcelService) getTemplateRows(f *excelize.File, sheet string, rowsCount int) ([]models.ExcelTemplateRow, error) {
+	if rowsCount <= 0 {
+		return nil, nil
+	}
+
+	rows, err := f.GetRows(sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	mergeCells, err := f.GetMergeCells(sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	templateRows := make([]models.ExcelTemplateRow, rowsCount)
+
+	for i := range templateRows {
+		rowIndex := i + 1
+
+		templateRows[i].Height, err = f.GetRowHeight(sheet, rowIndex)
+		if err != nil {
+			return nil, err
+		}
+
+		colsCount := 0
+		if i < len(rows) {
+			colsCount = len(rows[i])
+		}
+
+		for columnIndex := 1; columnIndex <= colsCount; columnIndex++ {
+			cell, err := s.getTemplateCell(f, sheet, columnIndex, rowIndex)
+			if err != nil {
+				return nil, err
+			}
+
+			templateRows[i].Cells = append(templateRows[i].Cells, cell)
+		}
+	}
+
+	for _, mergeCell := range mergeCells {
+		_, rowIndex, err := excelize.CellNameToCoordinates(mergeCell.GetStartAxis())
+		if err != nil {
+			return nil, err
+		}
+
+		if rowIndex <= rowsCount {
+			templateRows[rowIndex-1].MergeCells = append(templateRows[rowIndex-1].MergeCells,
+				[]string{mergeCell.GetStartAxis(), mergeCell.GetEndAxis()})
+		}
+	}
+
+	return templateRows, nil
+}
+
+func (s *ExcelService) getTemplateCell(f *excelize.File, sheet string, columnIndex, rowIndex int) (excelize.Cell, error) {
+	cellName, err := excelize.CoordinatesToCellName(columnIndex, rowIndex)
+	if err != nil {
+		return excelize.Cell{}, err
+	}
+
+	style, err := f.GetCellStyle(sheet, cellName)
+	if err != nil {
+		return excelize.Cell{}, err
+	}
+
+	formula, err := f.GetCellFormula(sheet, cellName)
+	if err != nil {
+		return excelize.Cell{}, err
+	}
+
+	value, err := f.GetCellValue(sheet, cellName, excelize.Options{RawCellValue: true})
+	if err != nil {
+		return excelize.Cell{}, err
+	}
+
+	cellType, err := f.GetCellType(sheet, cellName)
+	if err != nil {
+		return excelize.Cell{}, err
+	}
+
+	// numbers are kept as numbers to save their format
+	if cellType == excelize.CellTypeUnset || cellType == excelize.CellTypeNumber {
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			return excelize.Cell{StyleID: style, Formula: formula, Value: number}, nil
+		}
+	}
+
+	return excelize.Cell{StyleID: style, Formula: formula, Value: value}, nil
+}
+
+// setTemplateColWidths keeps widths of the sheet columns to the left of the grid.
+func (s *ExcelService) setTemplateColWidths(f *excelize.File, sw *excelize.StreamWriter, context *models.ExcelContext) error {
+	for columnIndex := 1; columnIndex < context.FirstColumnIndex; columnIndex++ {
+		col, err := excelize.ColumnNumberToName(columnIndex)
+		if err != nil {
+			return err
+		}
+
+		width, err := f.GetColWidth(context.Sheet, col)
+		if err != nil {
+			return err
+		}
+
+		err = sw.SetColWidth(columnIndex, columnIndex, width)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *ExcelService) writeTemplateRows(sw *excelize.StreamWriter, templateRows []models.ExcelTemplateRow, context *models.ExcelContext) error {
+	for _, templateRow := range templateRows {
+		err := s.writeRow(sw, templateRow.Cells, excelize.RowOpts{Height: templateRow.Height}, context)
+		if err != nil {
+			return err
+		}
+
+		for _, mergeCell := range templateRow.MergeCells {
+			err = sw.MergeCell(mergeCell[0], mergeCell[1])
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// fillTemplateHeader puts the report title to the header anchor,
+// it must be called before the detail sheets are written as the title can be placed above the grid.
+func (s *ExcelService) fillTemplateHeader(f *excelize.File, title string) error {
+	headerAnchor, err := s.getTemplateAnchor(f, TemplateHeaderName)
+	if err != nil {
+		return err
+	}
+
+	if headerAnchor == nil {
+		return nil
+	}
+
+	cell, err := excelize.CoordinatesToCellName(headerAnchor.ColumnIndex, headerAnchor.RowIndex)
+	if err != nil {
+		return err
+	}
+
+	return f.SetCellValue(headerAnchor.Sheet, cell, title)
+}
+
+// fillTemplateTotals puts hours and cost totals of the detail sheets to the totals anchor and the next cell.
+func (s *ExcelService) fillTemplateTotals(f *excelize.File, contexts []*models.ExcelContext) error {
+	totalsAnchor, err := s.getTemplateAnchor(f, TemplateTotalsName)
+	if err != nil {
+		return err
+	}
+
+	if totalsAnchor == nil {
+		return nil
+	}
+
+	var hoursCells, costCells []string
+	totalHours := 0.0
+	totalCost := 0.0
+
+	for _, context := range contexts {
+		// cells of sheets written by the stream writer can't be changed
+		if context.Sheet == totalsAnchor.Sheet {
+			return errors.New("report template totals can't be placed on the grid sheet: " + TemplateTotalsName)
+		}
+
+		hoursCell, err := s.getColumnCellName(constants.ColumnHours, context.LastRowIndex, context)
+		if err != nil {
+			return err
+		}
+
+		costCell, err := s.getColumnCellName(constants.ColumnCost, context.LastRowIndex, context)
+		if err != nil {
+			return err
+		}
+
+		sheetRef := s.getSheetReference(context.Sheet)
+		hoursCells = append(hoursCells, sheetRef+hoursCell)
+		costCells = append(costCells, sheetRef+costCell)
+		totalHours += context.TotalHours
+		totalCost += context.TotalCost
+	}
+
+	hoursCell, err := excelize.CoordinatesToCellName(totalsAnchor.ColumnIndex, totalsAnchor.RowIndex)
+	if err != nil {
+		return err
+	}
+
+	costCell, err := excelize.CoordinatesToCellName(totalsAnchor.ColumnIndex+1, totalsAnchor.RowIndex)
+	if err != nil {
+		return err
+	}
+
+	err = s.setCellFormulaValue(f, totalsAnchor.Sheet, hoursCell, "sum("+strings.Join(hoursCells, ",")+")", s.pricingService.roundHours(totalHours))
+	if err != nil {
+		return err
+	}
+
+	return s.setCellFormulaValue(f, totalsAnchor.Sheet, costCell, "sum("+strings.Join(costCells, ",")+")", totalCost)
+}
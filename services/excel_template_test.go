@@ -0,0 +1,171 @@
+package services
+
+import (
+	"github.com/xuri/excelize/v2"
+	"path/filepath"
+	"tempo-worklog/models"
+	"testing"
+)
+
+// saveTestTemplate saves a template with the Cover and Worklog sheets and the given defined names.
+func saveTestTemplate(t *testing.T, definedNames map[string]string) string {
+	t.Helper()
+
+	f := excelize.NewFile()
+	defer f.Close()
+
+	f.SetSheetName("Sheet1", "Cover")
+
+	_, err := f.NewSheet("Worklog")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = f.SetCellValue("Worklog", "A1", "ACME")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, refersTo := range definedNames {
+		err = f.SetDefinedName(&excelize.DefinedName{Name: name, RefersTo: refersTo})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	filePath := filepath.Join(t.TempDir(), "template.xlsx")
+
+	err = f.SaveAs(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return filePath
+}
+
+func TestGetTemplateAnchor(t *testing.T) {
+	tests := []struct {
+		name     string
+		refersTo string
+		want     *models.ExcelAnchor
+		wantErr  string
+	}{
+		{"cell", "Worklog!$B$3", &models.ExcelAnchor{Sheet: "Worklog", ColumnIndex: 2, RowIndex: 3}, ""},
+		{"range", "Cover!$C$4:$D$4", &models.ExcelAnchor{Sheet: "Cover", ColumnIndex: 3, RowIndex: 4}, ""},
+		{"unknown sheet", "Other!$A$1", nil, "report template name refers to unknown sheet: ReportGrid"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			templateFilePath := saveTestTemplate(t, map[string]string{TemplateGridName: test.refersTo})
+
+			f, err := excelize.OpenFile(templateFilePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			anchor, err := newTestExcelService("", models.ReportAppConfig{}).getTemplateAnchor(f, TemplateGridName)
+			if len(test.wantErr) > 0 {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("getTemplateAnchor() error = %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if *anchor != *test.want {
+				t.Errorf("getTemplateAnchor() = %+v, want %+v", *anchor, *test.want)
+			}
+		})
+	}
+}
+
+func TestSaveTemplate(t *testing.T) {
+	templateFilePath := saveTestTemplate(t, map[string]string{
+		TemplateHeaderName: "Cover!$B$2",
+		TemplateGridName:   "Worklog!$B$3",
+		TemplateTotalsName: "Cover!$B$4",
+	})
+
+	filePath := filepath.Join(t.TempDir(), "report.xlsx")
+	reportConfig := models.ReportAppConfig{Granularity: "day", Layout: "single"}
+
+	err := NewExcelService(filePath, templateFilePath, reportConfig, newTestLayoutConfig(), NewPricingService(reportConfig.Granularity)).
+		Save(newTestWorklog("PRJ"), "2023-01-02", "2023-01-08")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	wantCells := []struct {
+		sheet string
+		cell  string
+		want  string
+	}{
+		{"Cover", "B2", "Jan 2 - Jan 8"},
+		{"Cover", "B4", "1"},
+		{"Cover", "C4", "10"},
+		{"Worklog", "A1", "ACME"},
+		{"Worklog", "B3", "Name"},
+		{"Worklog", "B4", "PRJ"},
+		{"Worklog", "B5", "John Smith"},
+	}
+
+	for _, wantCell := range wantCells {
+		if value := getCell(t, f, wantCell.sheet, wantCell.cell); value != wantCell.want {
+			t.Errorf("%s!%s is %q, want %q", wantCell.sheet, wantCell.cell, value, wantCell.want)
+		}
+	}
+
+	formula, err := f.GetCellFormula("Cover", "B4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if formula != "sum('Worklog'!F7)" {
+		t.Errorf("totals formula is %q, want %q", formula, "sum('Worklog'!F7)")
+	}
+}
+
+func TestSaveTemplateErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		definedNames map[string]string
+		layout       string
+		wantErr      string
+	}{
+		{
+			"totals on grid sheet",
+			map[string]string{TemplateGridName: "Worklog!$B$3", TemplateTotalsName: "Worklog!$B$1"},
+			"single",
+			"report template totals can't be placed on the grid sheet: ReportTotals",
+		},
+		{
+			"grid with per project layout",
+			map[string]string{TemplateGridName: "Worklog!$B$3"},
+			"per_project",
+			"report template grid is supported by single layout only",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			templateFilePath := saveTestTemplate(t, test.definedNames)
+			filePath := filepath.Join(t.TempDir(), "report.xlsx")
+			reportConfig := models.ReportAppConfig{Granularity: "day", Layout: test.layout}
+
+			err := NewExcelService(filePath, templateFilePath, reportConfig, newTestLayoutConfig(), NewPricingService(reportConfig.Granularity)).
+				Save(newTestWorklog("PRJ"), "2023-01-02", "2023-01-08")
+			if err == nil || err.Error() != test.wantErr {
+				t.Fatalf("Save() error = %v, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
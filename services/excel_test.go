@@ -0,0 +1,903 @@
+package services
+
+import (
+	"github.com/xuri/excelize/v2"
+	"math"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"tempo-worklog/constants"
+	"tempo-worklog/models"
+	"testing"
+)
+
+func newTestExcelService(filePath string, reportConfig models.ReportAppConfig) *ExcelService {
+	if len(reportConfig.Granularity) == 0 {
+		reportConfig.Granularity = constants.GranularityDay
+	}
+	if len(reportConfig.Layout) == 0 {
+		reportConfig.Layout = constants.LayoutSingle
+	}
+
+	return NewExcelService(filePath, "", reportConfig, newTestLayoutConfig(), NewPricingService(reportConfig.Granularity))
+}
+
+func newTestLayoutConfig() models.ReportLayoutAppConfig {
+	return models.ReportLayoutAppConfig{
+		Theme: models.ReportThemeAppConfig{
+			FontFamily:      "Calibri",
+			FontSize:        12,
+			FontColor:       "#000000",
+			HeaderFontSize:  13,
+			HeaderFontColor: "#ffffff",
+			HeaderColor:     "#2487bc",
+			ProjectColor:    "#bee0f2",
+			TeamColor:       "#c9dfea",
+			UserColor:       "#d3e2ea",
+			EpicColor:       "#e7eff3",
+			TotalColor:      "#53aede",
+			WeekendColor:    "#FEC7CE",
+		},
+		Columns: []models.ReportColumnAppConfig{
+			{Name: constants.ColumnName},
+			{Name: constants.ColumnPosition},
+			{Name: constants.ColumnTask},
+			{Name: constants.ColumnRate},
+			{Name: constants.ColumnHours},
+			{Name: constants.ColumnCost},
+		},
+	}
+}
+
+func newTestWorklog(projectKeys ...string) *models.Worklog {
+	worklog := &models.Worklog{}
+
+	for _, projectKey := range projectKeys {
+		worklog.Projects = append(worklog.Projects, models.Project{
+			Key: projectKey,
+			Users: []models.User{{
+				AccountId:   "1",
+				DisplayName: "John Smith",
+				Rate:        10,
+				Issues: []models.Issue{{
+					Key:     projectKey + "-1",
+					Summary: "Task",
+					Efforts: []models.Effort{{
+						Date:             "2023-01-02",
+						TimeSpentSeconds: 3600,
+						UnroundedSeconds: 3600,
+						LoggedSeconds:    3600,
+						BillableSeconds:  3600,
+					}},
+				}},
+			}},
+		})
+	}
+
+	return worklog
+}
+
+// saveTestReport saves the worklog for the first week of 2023 and opens the saved file.
+func saveTestReport(t *testing.T, reportConfig models.ReportAppConfig, worklog *models.Worklog) *excelize.File {
+	t.Helper()
+
+	filePath := filepath.Join(t.TempDir(), "report.xlsx")
+
+	err := newTestExcelService(filePath, reportConfig).Save(worklog, "2023-01-02", "2023-01-08")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		f.Close()
+	})
+
+	return f
+}
+
+// getCell returns the value of the cell or fails the test.
+func getCell(t *testing.T, f *excelize.File, sheet, cell string) string {
+	t.Helper()
+
+	value, err := f.GetCellValue(sheet, cell)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return value
+}
+
+// findRow returns the number of the first row containing the value or fails the test.
+func findRow(t *testing.T, f *excelize.File, sheet, value string) int {
+	t.Helper()
+
+	rows, err := f.GetRows(sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, row := range rows {
+		for _, cell := range row {
+			if cell == value {
+				return i + 1
+			}
+		}
+	}
+
+	t.Fatalf("row of %q is missing in %s sheet", value, sheet)
+	return 0
+}
+
+func TestSaveValuesOnly(t *testing.T) {
+	tests := []struct {
+		name        string
+		valuesOnly  bool
+		wantFormula bool
+	}{
+		{name: "formulas with cached values", wantFormula: true},
+		{name: "values only", valuesOnly: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			worklog := newTestWorklog("PRJ")
+			user := &worklog.Projects[0].Users[0]
+			user.Issues = append(user.Issues, models.Issue{
+				Key:     "PRJ-2",
+				Summary: "Review",
+				Efforts: []models.Effort{{Date: "2023-01-03", TimeSpentSeconds: 9000}},
+			})
+
+			f := saveTestReport(t, models.ReportAppConfig{ValuesOnly: test.valuesOnly}, worklog)
+			sheet := f.GetSheetList()[1]
+
+			for _, label := range []string{"John Smith", "Total"} {
+				row := strconv.Itoa(findRow(t, f, sheet, label))
+
+				for cell, want := range map[string]string{"E" + row: "3.5", "F" + row: "35"} {
+					value, err := f.GetCellValue(sheet, cell, excelize.Options{RawCellValue: true})
+					if err != nil {
+						t.Fatal(err)
+					}
+					if value != want {
+						t.Errorf("%s of %s row is %q, want %q", cell, label, value, want)
+					}
+
+					formula, err := f.GetCellFormula(sheet, cell)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if hasFormula := len(formula) > 0; hasFormula != test.wantFormula {
+						t.Errorf("%s of %s row has formula %q, want formula: %v", cell, label, formula, test.wantFormula)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSaveDetailSheetPeriods(t *testing.T) {
+	tests := []struct {
+		granularity string
+		wantCells   map[string]string // period cells of issue row
+	}{
+		{granularity: constants.GranularityDay, wantCells: map[string]string{"G": "1", "H": "", "M": "2"}},
+		{granularity: constants.GranularityWeek, wantCells: map[string]string{"G": "3", "H": ""}},
+		{granularity: constants.GranularityMonth, wantCells: map[string]string{"G": "3", "H": ""}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.granularity, func(t *testing.T) {
+			worklog := newTestWorklog("PRJ")
+			issue := &worklog.Projects[0].Users[0].Issues[0]
+			issue.Efforts = append(issue.Efforts, models.Effort{Date: "2023-01-08", TimeSpentSeconds: 7200})
+
+			f := saveTestReport(t, models.ReportAppConfig{Granularity: test.granularity}, worklog)
+			sheet := f.GetSheetList()[1]
+			row := strconv.Itoa(findRow(t, f, sheet, "PRJ-1: Task"))
+
+			for col, want := range test.wantCells {
+				if value := getCell(t, f, sheet, col+row); value != want {
+					t.Errorf("%s%s is %q, want %q", col, row, value, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSaveDetailSheetOfManyUsers(t *testing.T) {
+	worklog := newTestWorklog("PRJ")
+	project := &worklog.Projects[0]
+	template := project.Users[0]
+	project.Users = nil
+
+	usersCount := 1000
+	for i := 0; i < usersCount; i++ {
+		user := template
+		user.AccountId = strconv.Itoa(i)
+		user.DisplayName = "User " + strconv.Itoa(i)
+		project.Users = append(project.Users, user)
+	}
+
+	f := saveTestReport(t, models.ReportAppConfig{}, worklog)
+	sheet := f.GetSheetList()[1]
+
+	// header, project, user and issue rows of every user, total
+	wantRow := 1 + 1 + 2*usersCount + 1
+	if row := findRow(t, f, sheet, "Total"); row != wantRow {
+		t.Errorf("total row is %d, want %d", row, wantRow)
+	}
+
+	value, err := f.GetCellValue(sheet, "E"+strconv.Itoa(wantRow), excelize.Options{RawCellValue: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != strconv.Itoa(usersCount) {
+		t.Errorf("total hours are %s, want %d", value, usersCount)
+	}
+}
+
+func TestGetSumFormula(t *testing.T) {
+	tests := []struct {
+		rowIndexes []int
+		want       string
+	}{
+		{rowIndexes: []int{3}, want: "sum(E3)"},
+		{rowIndexes: []int{3, 4, 5}, want: "sum(E3:E5)"},
+		{rowIndexes: []int{3, 5, 6, 9}, want: "sum(E3,E5:E6,E9)"},
+	}
+
+	s := &ExcelService{}
+	for _, test := range tests {
+		if formula := s.getSumFormula("E", test.rowIndexes); formula != test.want {
+			t.Errorf("getSumFormula(%v) = %q, want %q", test.rowIndexes, formula, test.want)
+		}
+	}
+}
+
+func TestGetStylesFonts(t *testing.T) {
+	s := newTestExcelService("report.xlsx", models.ReportAppConfig{})
+	s.layoutConfig.Theme.FontFamily = "Arial"
+
+	f := excelize.NewFile()
+	styles, err := s.getStyles(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		style    int
+		wantSize float64
+	}{
+		{name: "header", style: styles.Header, wantSize: 13},
+		{name: "user", style: styles.User, wantSize: 12},
+		{name: "issue period", style: styles.IssuePeriod, wantSize: 12},
+		{name: "group", style: styles.Group, wantSize: 12},
+		{name: "total", style: styles.Total, wantSize: 12},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			font := f.Styles.Fonts.Font[*f.Styles.CellXfs.Xf[test.style].FontID]
+			if *font.Name.Val != "Arial" || *font.Sz.Val != test.wantSize {
+				t.Errorf("font is %s %v, want Arial %v", *font.Name.Val, *font.Sz.Val, test.wantSize)
+			}
+		})
+	}
+}
+
+func TestApplyWeekendStyle(t *testing.T) {
+	tests := []struct {
+		name           string
+		periods        []models.ReportPeriod
+		weekendPeriods map[int]bool
+		want           []bool // weekend style of period cells
+	}{
+		{name: "weekend days", periods: []models.ReportPeriod{{}, {IsWeekend: true}}, want: []bool{false, true}},
+		{name: "weeks with weekend hours", periods: []models.ReportPeriod{{}, {}}, weekendPeriods: map[int]bool{1: true}, want: []bool{false, true}},
+		{name: "weeks without weekend hours", periods: []models.ReportPeriod{{}, {}}, want: []bool{false, false}},
+	}
+
+	s := &ExcelService{}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			context := &models.ExcelContext{Periods: test.periods, FirstDateColumnIndex: 2, Styles: models.ExcelStyles{Weekend: 7}}
+			row := make([]interface{}, 3)
+
+			s.applyWeekendStyle(row, test.weekendPeriods, context)
+
+			for i, want := range test.want {
+				cell, _ := row[i+1].(excelize.Cell)
+				if isWeekend := cell.StyleID == context.Styles.Weekend; isWeekend != want {
+					t.Errorf("weekend style of period %d is %v, want %v", i, isWeekend, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSaveTotalsOfUserRows(t *testing.T) {
+	worklog := newTestWorklog("PRJ", "OTH")
+	user := &worklog.Projects[0].Users[0]
+	user.Issues = append(user.Issues, models.Issue{
+		Key:     "PRJ-2",
+		Summary: "Review",
+		Efforts: []models.Effort{{Date: "2023-01-02", TimeSpentSeconds: 1800}},
+	})
+
+	f := saveTestReport(t, models.ReportAppConfig{}, worklog)
+	sheet := f.GetSheetList()[1]
+
+	// header, PRJ project, user, 2 issues, OTH project, user, issue, total
+	wantFormulas := map[string]string{
+		"E3": "sum(G3:M3)",
+		"E9": "sum(E3,E7)",
+		"F9": "sum(F3,F7)",
+		"G9": "sum(G3,G7)",
+	}
+
+	for cell, want := range wantFormulas {
+		formula, err := f.GetCellFormula(sheet, cell)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if formula != want {
+			t.Errorf("%s formula is %q, want %q", cell, formula, want)
+		}
+	}
+}
+
+func TestGetColumns(t *testing.T) {
+	tests := []struct {
+		name      string
+		columns   []models.ReportColumnAppConfig
+		approvals bool
+		want      []models.ReportColumnAppConfig
+		wantErr   string
+	}{
+		{
+			name: "defaults",
+			columns: []models.ReportColumnAppConfig{
+				{Name: "Task"}, {Name: constants.ColumnHours, Header: "Effort"}, {Name: constants.ColumnCost, Width: 12},
+			},
+			want: []models.ReportColumnAppConfig{
+				{Name: constants.ColumnTask, Header: "Task", Width: 60},
+				{Name: constants.ColumnHours, Header: "Effort", Width: 10},
+				{Name: constants.ColumnCost, Header: "Total cost", Width: 12},
+			},
+		},
+		{
+			name:      "approvals",
+			columns:   []models.ReportColumnAppConfig{{Name: "hours"}, {Name: "cost"}},
+			approvals: true,
+			want: []models.ReportColumnAppConfig{
+				{Name: constants.ColumnHours, Header: "Hours", Width: 10},
+				{Name: constants.ColumnCost, Header: "Total cost", Width: 20},
+				{Name: constants.ColumnApproval, Header: "Approval", Width: 12},
+			},
+		},
+		{
+			name:      "approvals with configured column",
+			columns:   []models.ReportColumnAppConfig{{Name: "approval", Width: 20}, {Name: "hours"}, {Name: "cost"}},
+			approvals: true,
+			want: []models.ReportColumnAppConfig{
+				{Name: constants.ColumnApproval, Header: "Approval", Width: 20},
+				{Name: constants.ColumnHours, Header: "Hours", Width: 10},
+				{Name: constants.ColumnCost, Header: "Total cost", Width: 20},
+			},
+		},
+		{
+			name:    "unknown",
+			columns: []models.ReportColumnAppConfig{{Name: "hours"}, {Name: "cost"}, {Name: "comment"}},
+			wantErr: "unknown report column: comment",
+		},
+		{
+			name:    "duplicated",
+			columns: []models.ReportColumnAppConfig{{Name: "hours"}, {Name: "cost"}, {Name: "Hours"}},
+			wantErr: "duplicated report column: Hours",
+		},
+		{
+			name:    "no cost",
+			columns: []models.ReportColumnAppConfig{{Name: "name"}, {Name: "hours"}},
+			wantErr: "report column is required: cost",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := &ExcelService{
+				reportConfig: models.ReportAppConfig{Approvals: test.approvals},
+				layoutConfig: models.ReportLayoutAppConfig{Columns: test.columns},
+			}
+
+			columns, err := s.getColumns()
+			if len(test.wantErr) > 0 {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("getColumns() error = %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(columns, test.want) {
+				t.Errorf("getColumns() = %+v, want %+v", columns, test.want)
+			}
+		})
+	}
+}
+
+func TestSaveCustomColumns(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "report.xlsx")
+
+	layoutConfig := newTestLayoutConfig()
+	layoutConfig.Columns = []models.ReportColumnAppConfig{
+		{Name: constants.ColumnTask},
+		{Name: constants.ColumnHours, Header: "Effort"},
+		{Name: constants.ColumnCost},
+	}
+
+	reportConfig := models.ReportAppConfig{Granularity: constants.GranularityDay, Layout: constants.LayoutSingle}
+	err := NewExcelService(filePath, "", reportConfig, layoutConfig, NewPricingService(reportConfig.Granularity)).Save(newTestWorklog("PRJ"), "2023-01-02", "2023-01-08")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	sheet := f.GetSheetList()[1]
+	for cell, want := range map[string]string{"A1": "Task", "B1": "Effort", "C1": "Total cost", "D1": "1/2"} {
+		if value := getCell(t, f, sheet, cell); value != want {
+			t.Errorf("header %s is %q, want %q", cell, value, want)
+		}
+	}
+
+	row := strconv.Itoa(findRow(t, f, sheet, "PRJ-1: Task"))
+	for cell, want := range map[string]string{"B" + row: "1", "C" + row: "10"} {
+		value, err := f.GetCellValue(sheet, cell, excelize.Options{RawCellValue: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value != want {
+			t.Errorf("%s of issue row is %q, want %q", cell, value, want)
+		}
+	}
+}
+
+func TestSaveTeamRows(t *testing.T) {
+	worklog := newTestWorklog("PRJ")
+	user := worklog.Projects[0].Users[0]
+	worklog.Projects[0].Users = nil
+
+	for _, team := range []string{"Dev", "", "Dev"} {
+		user.AccountId += "1"
+		user.Team = team
+		worklog.Projects[0].Users = append(worklog.Projects[0].Users, user)
+	}
+
+	f := saveTestReport(t, models.ReportAppConfig{Teams: true}, worklog)
+	sheet := "Jan 2 - Jan 8"
+
+	tests := []struct {
+		row     string
+		name    string
+		formula string
+		hours   string
+	}{
+		{"3", "Dev", "sum(E4,E6)", "2"},
+		{"8", ReportNoTeamLabel, "sum(E9)", "1"},
+		{"11", "Total", "sum(E4,E6,E9)", "3"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if name := getCell(t, f, sheet, "A"+test.row); name != test.name {
+				t.Errorf("A%s is %q, want %q", test.row, name, test.name)
+			}
+
+			formula, err := f.GetCellFormula(sheet, "E"+test.row)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if formula != test.formula {
+				t.Errorf("E%s formula is %q, want %q", test.row, formula, test.formula)
+			}
+
+			if hours := getCell(t, f, sheet, "E"+test.row); hours != test.hours {
+				t.Errorf("E%s is %q, want %q", test.row, hours, test.hours)
+			}
+		})
+	}
+}
+
+func TestGetIssueGroups(t *testing.T) {
+	issues := []models.Issue{
+		{Key: "PRJ-1", Attribute: "Support"},
+		{Key: "PRJ-2", EpicKey: "PRJ-20", EpicSummary: "Billing"},
+		{Key: "PRJ-3", EpicKey: "PRJ-10", EpicSummary: "Login", Attribute: "Development"},
+		{Key: "PRJ-4", EpicKey: "PRJ-20", EpicSummary: "Billing", Attribute: "Support"},
+	}
+
+	tests := []struct {
+		name         string
+		reportConfig models.ReportAppConfig
+		want         []string
+	}{
+		{"off", models.ReportAppConfig{}, []string{": PRJ-1 PRJ-2 PRJ-3 PRJ-4"}},
+		{"epics", models.ReportAppConfig{Epics: true}, []string{
+			"PRJ-20: Billing: PRJ-2 PRJ-4",
+			"PRJ-10: Login: PRJ-3",
+			ReportNoEpicLabel + ": PRJ-1",
+		}},
+		{"attribute", models.ReportAppConfig{GroupBy: "_Account_"}, []string{
+			"Support: PRJ-1 PRJ-4",
+			"Development: PRJ-3",
+			"(no _Account_): PRJ-2",
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newTestExcelService("", test.reportConfig)
+
+			var got []string
+			for _, group := range s.getIssueGroups(issues) {
+				var keys []string
+				for _, issue := range group.Issues {
+					keys = append(keys, issue.Key)
+				}
+				got = append(got, group.Label+": "+strings.Join(keys, " "))
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("getIssueGroups() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSaveEpicRows(t *testing.T) {
+	worklog := newTestWorklog("PRJ")
+	user := &worklog.Projects[0].Users[0]
+	user.Issues[0].EpicKey = "PRJ-10"
+	user.Issues[0].EpicSummary = "Epic"
+	user.Issues = append(user.Issues, models.Issue{
+		Key:     "PRJ-2",
+		Summary: "Bug",
+		Efforts: []models.Effort{{Date: "2023-01-03", TimeSpentSeconds: 7200, UnroundedSeconds: 7200}},
+	})
+
+	f := saveTestReport(t, models.ReportAppConfig{Epics: true}, worklog)
+	sheet := "Jan 2 - Jan 8"
+
+	tests := []struct {
+		row     string
+		task    string
+		formula string
+	}{
+		{"4", "PRJ-10: Epic", "sum(E5)"},
+		{"5", "PRJ-1: Task", ""},
+		{"6", ReportNoEpicLabel, "sum(E7)"},
+		{"7", "PRJ-2: Bug", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.task, func(t *testing.T) {
+			if task := getCell(t, f, sheet, "C"+test.row); task != test.task {
+				t.Errorf("C%s is %q, want %q", test.row, task, test.task)
+			}
+
+			if len(test.formula) == 0 {
+				return
+			}
+
+			formula, err := f.GetCellFormula(sheet, "E"+test.row)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if formula != test.formula {
+				t.Errorf("E%s formula is %q, want %q", test.row, formula, test.formula)
+			}
+		})
+	}
+
+	// user totals are summed from issue rows, not epic rows
+	formula, err := f.GetCellFormula(sheet, "E3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if formula != "sum(G3:M3)" {
+		t.Errorf("E3 formula is %q, want %q", formula, "sum(G3:M3)")
+	}
+}
+
+func TestSaveUserReport(t *testing.T) {
+	worklog := newTestWorklog("PRJ", "OTH")
+	worklog.By = constants.ReportByUser
+
+	f := saveTestReport(t, models.ReportAppConfig{}, worklog)
+	sheet := "Jan 2 - Jan 8"
+
+	tests := []struct {
+		row     string
+		name    string
+		formula string
+	}{
+		{"2", "John Smith", "sum(E3,E5)"},
+		{"3", "PRJ", "sum(G3:M3)"},
+		{"5", "OTH", "sum(G5:M5)"},
+		{"7", "Total", "sum(E3,E5)"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if name := getCell(t, f, sheet, "A"+test.row); name != test.name {
+				t.Errorf("A%s is %q, want %q", test.row, name, test.name)
+			}
+
+			formula, err := f.GetCellFormula(sheet, "E"+test.row)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if formula != test.formula {
+				t.Errorf("E%s formula is %q, want %q", test.row, formula, test.formula)
+			}
+		})
+	}
+
+	filePath := filepath.Join(t.TempDir(), "report.xlsx")
+	err := newTestExcelService(filePath, models.ReportAppConfig{Layout: constants.LayoutPerProject}).Save(worklog, "2023-01-02", "2023-01-08")
+	if err == nil || err.Error() != "user report is supported by single layout only" {
+		t.Errorf("Save() error = %v, want user report layout error", err)
+	}
+}
+
+func TestSaveBillingColumns(t *testing.T) {
+	worklog := newTestWorklog("PRJ")
+	worklog.Projects[0].Users[0].Issues[0].Efforts[0].BillableSeconds = 1800
+
+	filePath := filepath.Join(t.TempDir(), "report.xlsx")
+	layoutConfig := newTestLayoutConfig()
+	layoutConfig.Columns = []models.ReportColumnAppConfig{
+		{Name: constants.ColumnTask},
+		{Name: constants.ColumnRate},
+		{Name: constants.ColumnHours},
+		{Name: constants.ColumnCost},
+		{Name: constants.ColumnLoggedHours},
+		{Name: constants.ColumnBillableHours},
+		{Name: constants.ColumnBillableCost},
+	}
+	reportConfig := models.ReportAppConfig{Granularity: constants.GranularityDay, Layout: constants.LayoutSingle}
+
+	err := NewExcelService(filePath, "", reportConfig, layoutConfig, NewPricingService(reportConfig.Granularity)).Save(worklog, "2023-01-02", "2023-01-08")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	sheet := "Jan 2 - Jan 8"
+
+	tests := []struct {
+		cell        string
+		wantValue   string
+		wantFormula string
+	}{
+		{"E1", "Logged hours", ""},
+		{"E4", "1", ""},
+		{"F4", "0.5", ""},
+		{"G4", "5", "B4*F4"},
+		{"F3", "0.5", "sum(F4)"},
+		{"G5", "5", "sum(G3)"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.cell, func(t *testing.T) {
+			if value := getCell(t, f, sheet, test.cell); value != test.wantValue {
+				t.Errorf("%s is %q, want %q", test.cell, value, test.wantValue)
+			}
+
+			formula, err := f.GetCellFormula(sheet, test.cell)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if formula != test.wantFormula {
+				t.Errorf("%s formula is %q, want %q", test.cell, formula, test.wantFormula)
+			}
+		})
+	}
+}
+
+func TestSaveWorkTypeRates(t *testing.T) {
+	worklog := newTestWorklog("PRJ")
+	user := &worklog.Projects[0].Users[0]
+	consulting := user.Issues[0]
+	consulting.WorkType = "Consulting"
+	consulting.Rate = 50
+	user.Issues = append(user.Issues, consulting)
+
+	f := saveTestReport(t, models.ReportAppConfig{}, worklog)
+	sheet := "Jan 2 - Jan 8"
+
+	tests := []struct {
+		cell        string
+		wantValue   string
+		wantFormula string
+	}{
+		{"C4", "PRJ-1: Task", ""},
+		{"C5", "PRJ-1: Task (Consulting)", ""},
+		{"D5", "50", ""},
+		{"F5", "50", "D5*E5"},
+		{"F3", "60", "sum(F4:F5)"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.cell, func(t *testing.T) {
+			if value := getCell(t, f, sheet, test.cell); value != test.wantValue {
+				t.Errorf("%s is %q, want %q", test.cell, value, test.wantValue)
+			}
+
+			formula, err := f.GetCellFormula(sheet, test.cell)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if formula != test.wantFormula {
+				t.Errorf("%s formula is %q, want %q", test.cell, formula, test.wantFormula)
+			}
+		})
+	}
+}
+
+func TestSavePerProjectKeepsFixedSheets(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "report.xlsx")
+	reportConfig := models.ReportAppConfig{Layout: constants.LayoutPerProject, DataSheet: true}
+
+	err := newTestExcelService(filePath, reportConfig).Save(newTestWorklog("DATA", "OTH"), "2023-01-02", "2023-01-08")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	sheets := map[string]bool{}
+	for _, sheet := range f.GetSheetList() {
+		sheets[sheet] = true
+	}
+
+	for _, sheet := range []string{SummarySheetName, "DATA project", "OTH", TotalsSheetName, DataSheetName} {
+		if !sheets[sheet] {
+			t.Errorf("sheet %q is missing in %v", sheet, f.GetSheetList())
+		}
+	}
+
+	value, err := f.GetCellValue(DataSheetName, "A1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "Project" {
+		t.Errorf("data sheet header is %q, want %q", value, "Project")
+	}
+}
+
+func TestSaveScalesOverriddenCostToTotalRounding(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "report.xlsx")
+
+	worklog := newTestWorklog("PRJ")
+	project := &worklog.Projects[0]
+	project.Rounding = models.RoundingAppConfig{Mode: constants.RoundingModeUp, Minutes: 60, Scope: constants.RoundingScopeTotal}
+	project.Users[0].Issues = append(project.Users[0].Issues, models.Issue{
+		Key:      "PRJ-2",
+		Summary:  "Meeting",
+		WorkType: "Meeting",
+		Rate:     30,
+		Efforts:  []models.Effort{{Date: "2023-01-03", TimeSpentSeconds: 1800, LoggedSeconds: 1800, BillableSeconds: 1800}},
+	})
+
+	// 1 h at 10 and 0.5 h at 30 cost 25 for 1.5 h, rounded up to 2 h they cost 25 * 2 / 1.5
+	wantHours := 2.0
+	wantCost := 33.33
+
+	err := newTestExcelService(filePath, models.ReportAppConfig{}).Save(worklog, "2023-01-02", "2023-01-08")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	assertRowValues(t, f, f.GetSheetList()[1], "John Smith", "E", "F", wantHours, wantCost)
+	assertRowValues(t, f, SummarySheetName, "John Smith", "D", "E", wantHours, wantCost)
+}
+
+// assertRowValues checks cached hours and cost of the first row containing the label.
+func assertRowValues(t *testing.T, f *excelize.File, sheet, label, hoursCol, costCol string, wantHours, wantCost float64) {
+	t.Helper()
+
+	rows, err := f.GetRows(sheet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, row := range rows {
+		found := false
+		for _, value := range row {
+			found = found || value == label
+		}
+		if !found {
+			continue
+		}
+
+		for col, want := range map[string]float64{hoursCol: wantHours, costCol: wantCost} {
+			value, err := f.GetCellValue(sheet, col+strconv.Itoa(i+1), excelize.Options{RawCellValue: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(got-want) > 0.005 {
+				t.Errorf("%s!%s%d is %v, want %v", sheet, col, i+1, got, want)
+			}
+		}
+		return
+	}
+
+	t.Errorf("row of %q is missing in %s sheet", label, sheet)
+}
+
+func TestSaveListsPlannedUsersWithoutWorklog(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "report.xlsx")
+
+	worklog := newTestWorklog("PRJ")
+	worklog.Projects[0].Plans = []models.Plan{
+		{AccountId: "1", DisplayName: "John Smith", PlannedSeconds: 7200},
+		{AccountId: "2", DisplayName: "Jane Doe", PlannedSeconds: 5400},
+	}
+
+	err := newTestExcelService(filePath, models.ReportAppConfig{Plans: true}).Save(worklog, "2023-01-02", "2023-01-08")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := excelize.OpenFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	assertRowValues(t, f, PlanSheetName, "John Smith", "C", "D", 2, 1)
+	assertRowValues(t, f, PlanSheetName, "Jane Doe", "C", "D", 1.5, 0)
+	assertRowValues(t, f, PlanSheetName, "PRJ", "C", "D", 3.5, 1)
+
+	// planned hours and variance on Summary
+	assertRowValues(t, f, SummarySheetName, "John Smith", "H", "I", 2, -1)
+	assertRowValues(t, f, SummarySheetName, "(no position)", "H", "I", 2, -1)
+	assertRowValues(t, f, SummarySheetName, "PRJ", "H", "I", 3.5, -2.5)
+	assertRowValues(t, f, SummarySheetName, "Total", "H", "I", 3.5, -2.5)
+	if header := getCell(t, f, SummarySheetName, "J1"); header != "Variance %" {
+		t.Errorf("Summary!J1 is %q, want %q", header, "Variance %")
+	}
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"github.com/xuri/excelize/v2"
+	"strconv"
+	"tempo-worklog/constants"
+	"tempo-worklog/models"
+)
+
+const (
+	TotalsSheetName = "Totals"
+)
+
+func (s *ExcelService) prepareTotals(f *excelize.File, sheet string) error {
+	_, err := f.NewSheet(sheet)
+	if err != nil {
+		return err
+	}
+
+	alignment := excelize.Alignment{Horizontal: "center", Vertical: "center"}
+	borders := []excelize.Border{
+		{Type: "top", Color: "#000000", Style: 1},
+		{Type: "left", Color: "#000000", Style: 1},
+		{Type: "bottom", Color: "#000000", Style: 1},
+		{Type: "right", Color: "#000000", Style: 1},
+	}
+	font := excelize.Font{Family: s.layoutConfig.Theme.FontFamily, Size: s.layoutConfig.Theme.HeaderFontSize, Color: s.layoutConfig.Theme.HeaderFontColor, Bold: true}
+	fill := s.getThemeFill(s.layoutConfig.Theme.HeaderColor)
+	style, err := f.NewStyle(&excelize.Style{Alignment: &alignment, Font: &font, Border: borders, Fill: fill})
+	if err != nil {
+		return err
+	}
+
+	err = f.SetCellStyle(sheet, "A1", "C1", style)
+	if err != nil {
+		return err
+	}
+
+	err = f.SetRowHeight(sheet, 1, 25)
+	if err != nil {
+		return err
+	}
+
+	header := []interface{}{"Project", "Hours", "Total cost"}
+	err = f.SetSheetRow(sheet, "A1", &header)
+	if err != nil {
+		return err
+	}
+
+	err = f.SetColWidth(sheet, "A", "A", 30)
+	if err != nil {
+		return err
+	}
+
+	err = f.SetColWidth(sheet, "B", "B", 10)
+	if err != nil {
+		return err
+	}
+
+	err = f.SetColWidth(sheet, "C", "C", 20)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// fillTotals links each project row to the total row of the project sheet,
+// so the totals follow any rate edited in the project sheets.
+func (s *ExcelService) fillTotals(f *excelize.File, sheet string, contexts []*models.ExcelContext) error {
+	costStyle, err := f.NewStyle(&excelize.Style{NumFmt: 177})
+	if err != nil {
+		return err
+	}
+
+	rowIndex := 1
+	totalHours := 0.0
+	totalCost := 0.0
+
+	for _, context := range contexts {
+		hoursCell, err := s.getColumnCellName(constants.ColumnHours, context.LastRowIndex, context)
+		if err != nil {
+			return err
+		}
+
+		costCell, err := s.getColumnCellName(constants.ColumnCost, context.LastRowIndex, context)
+		if err != nil {
+			return err
+		}
+
+		rowIndex++
+		row := strconv.Itoa(rowIndex)
+		sheetRef := s.getSheetReference(context.Sheet)
+		totalHours += context.TotalHours
+		totalCost += context.TotalCost
+
+		err = f.SetCellValue(sheet, "A"+row, context.Sheet)
+		if err != nil {
+			return err
+		}
+
+		err = s.setCellFormulaValue(f, sheet, "B"+row, sheetRef+hoursCell, context.TotalHours)
+		if err != nil {
+			return err
+		}
+
+		err = f.SetCellStyle(sheet, "C"+row, "C"+row, costStyle)
+		if err != nil {
+			return err
+		}
+
+		err = s.setCellFormulaValue(f, sheet, "C"+row, sheetRef+costCell, context.TotalCost)
+		if err != nil {
+			return err
+		}
+	}
+
+	rowIndex++
+	row := strconv.Itoa(rowIndex)
+	lastRow := strconv.Itoa(rowIndex - 1)
+
+	alignment := excelize.Alignment{Horizontal: "center", Vertical: "center"}
+	font := excelize.Font{Family: s.layoutConfig.Theme.FontFamily, Size: s.layoutConfig.Theme.FontSize, Color: s.layoutConfig.Theme.FontColor, Bold: true}
+	fill := s.getThemeFill(s.layoutConfig.Theme.TotalColor)
+	style, err := f.NewStyle(&excelize.Style{Alignment: &alignment, Font: &font, Fill: fill})
+	if err != nil {
+		return err
+	}
+
+	err = f.SetCellStyle(sheet, "A"+row, "B"+row, style)
+	if err != nil {
+		return err
+	}
+
+	style, err = f.NewStyle(&excelize.Style{Alignment: &alignment, Font: &font, Fill: fill, NumFmt: 177})
+	if err != nil {
+		return err
+	}
+
+	err = f.SetCellStyle(sheet, "C"+row, "C"+row, style)
+	if err != nil {
+		return err
+	}
+
+	err = f.SetCellValue(sheet, "A"+row, "Total")
+	if err != nil {
+		return err
+	}
+
+	err = s.setCellFormulaValue(f, sheet, "B"+row, "sum(B2:B"+lastRow+")", s.pricingService.roundHours(totalHours))
+	if err != nil {
+		return err
+	}
+
+	err = s.setCellFormulaValue(f, sheet, "C"+row, "sum(C2:C"+lastRow+")", totalCost)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
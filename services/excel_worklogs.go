@@ -0,0 +1,121 @@
+package services
+
+import (
+	"github.com/xuri/excelize/v2"
+	"sort"
+	"strconv"
+	"tempo-worklog/constants"
+	"tempo-worklog/models"
+	"time"
+)
+
+const (
+	WorklogsSheetName = "Worklogs"
+	WorklogsTableName = "WorklogEntries"
+)
+
+// createWorklogsSheet puts one row per Tempo worklog with its description to the sheet formatted as Excel table,
+// so the worklogs can be filtered by user, issue or date.
+func (s *ExcelService) createWorklogsSheet(f *excelize.File, sheet string, worklog *models.Worklog) error {
+	_, err := f.NewSheet(sheet)
+	if err != nil {
+		return err
+	}
+
+	header := []interface{}{"Date", "Start time", "Project", "User", "Issue", "Summary", "Hours", "Description"}
+	err = f.SetSheetRow(sheet, "A1", &header)
+	if err != nil {
+		return err
+	}
+
+	var rows [][]interface{}
+
+	for _, project := range worklog.Projects {
+		for _, user := range project.Users {
+			for _, issue := range user.Issues {
+				for _, effort := range issue.Efforts {
+					date, err := time.Parse(constants.InputDateFormat, effort.Date)
+					if err != nil {
+						return err
+					}
+
+					for _, entry := range effort.Entries {
+						rows = append(rows, []interface{}{
+							date,
+							entry.StartTime,
+							project.Key,
+							user.DisplayName,
+							issue.Key,
+							issue.Summary,
+							s.pricingService.convertSecondsToHours(entry.TimeSpentSeconds),
+							entry.Description,
+						})
+					}
+				}
+			}
+		}
+	}
+
+	// by date and start time, the order of projects, users and issues is kept within the same time
+	sort.SliceStable(rows, func(i, j int) bool {
+		dateA, dateB := rows[i][0].(time.Time), rows[j][0].(time.Time)
+		if !dateA.Equal(dateB) {
+			return dateA.Before(dateB)
+		}
+		return rows[i][1].(string) < rows[j][1].(string)
+	})
+
+	for i := range rows {
+		err = f.SetSheetRow(sheet, "A"+strconv.Itoa(i+2), &rows[i])
+		if err != nil {
+			return err
+		}
+	}
+
+	lastRow := strconv.Itoa(len(rows) + 1)
+
+	dateStyle, err := f.NewStyle(&excelize.Style{NumFmt: 14})
+	if err != nil {
+		return err
+	}
+
+	err = f.SetCellStyle(sheet, "A2", "A"+lastRow, dateStyle)
+	if err != nil {
+		return err
+	}
+
+	descriptionStyle, err := f.NewStyle(&excelize.Style{Alignment: &excelize.Alignment{WrapText: true, Vertical: "top"}})
+	if err != nil {
+		return err
+	}
+
+	err = f.SetCellStyle(sheet, "H2", "H"+lastRow, descriptionStyle)
+	if err != nil {
+		return err
+	}
+
+	widths := []float64{12, 12, 15, 30, 15, 40, 10, 80}
+	for i, width := range widths {
+		col, err := excelize.ColumnNumberToName(i + 1)
+		if err != nil {
+			return err
+		}
+
+		err = f.SetColWidth(sheet, col, col, width)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = f.SetPanes(sheet, &excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"})
+	if err != nil {
+		return err
+	}
+
+	showRowStripes := true
+	return f.AddTable(sheet, "A1:H"+lastRow, &excelize.TableOptions{
+		Name:           WorklogsTableName,
+		StyleName:      "TableStyleMedium2",
+		ShowRowStripes: &showRowStripes,
+	})
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"reflect"
+	"tempo-worklog/models"
+	"testing"
+)
+
+func TestSaveWorklogsSheet(t *testing.T) {
+	worklog := newTestWorklog("PRJ", "OTH")
+	worklog.Projects[0].Users[0].Issues[0].Efforts[0].Entries = []models.WorklogEntry{
+		{StartTime: "14:00:00", TimeSpentSeconds: 1800, Description: "Review"},
+		{StartTime: "09:00:00", TimeSpentSeconds: 1800, Description: "Design\nof API"},
+	}
+	worklog.Projects[1].Users[0].Issues[0].Efforts[0].Entries = []models.WorklogEntry{
+		{StartTime: "10:00:00", TimeSpentSeconds: 3600, Description: "Call"},
+	}
+
+	tests := []struct {
+		name          string
+		worklogsSheet bool
+		wantRows      [][]string
+	}{
+		{name: "off"},
+		{name: "on", worklogsSheet: true, wantRows: [][]string{
+			{"Date", "Start time", "Project", "User", "Issue", "Summary", "Hours", "Description"},
+			{"01-02-23", "09:00:00", "PRJ", "John Smith", "PRJ-1", "Task", "0.5", "Design\nof API"},
+			{"01-02-23", "10:00:00", "OTH", "John Smith", "OTH-1", "Task", "1", "Call"},
+			{"01-02-23", "14:00:00", "PRJ", "John Smith", "PRJ-1", "Task", "0.5", "Review"},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f := saveTestReport(t, models.ReportAppConfig{WorklogsSheet: test.worklogsSheet}, worklog)
+
+			index, err := f.GetSheetIndex(WorklogsSheetName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hasSheet := index >= 0; hasSheet != test.worklogsSheet {
+				t.Fatalf("worklogs sheet exists: %v, want %v", hasSheet, test.worklogsSheet)
+			}
+			if !test.worklogsSheet {
+				return
+			}
+
+			rows, err := f.GetRows(WorklogsSheetName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(rows, test.wantRows) {
+				t.Errorf("worklogs sheet rows are %q, want %q", rows, test.wantRows)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"tempo-worklog/constants"
+	"tempo-worklog/models"
+)
+
+type ExportService struct {
+	reportFilePath string
+	theme          models.ReportThemeAppConfig
+	pricingService *PricingService
+}
+
+func NewExportService(reportFilePath string, theme models.ReportThemeAppConfig, pricingService *PricingService) *ExportService {
+	return &ExportService{reportFilePath: reportFilePath, theme: theme, pricingService: pricingService}
+}
+
+// GetFilePath returns path of the output next to the report file with extension of the format.
+func (s *ExportService) GetFilePath(format string) string {
+	return strings.TrimSuffix(s.reportFilePath, filepath.Ext(s.reportFilePath)) + "." + format
+}
+
+// SaveJson writes priced worklog nested by projects, users, issues and days.
+func (s *ExportService) SaveJson(worklog *models.Worklog, dateFrom, dateTo string) error {
+	report, err := s.GetReport(worklog, dateFrom, dateTo)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.GetFilePath(constants.OutputFormatJson), data, 0644)
+}
+
+// SaveCsv writes priced worklog as flat rows per project, user, issue and day.
+func (s *ExportService) SaveCsv(worklog *models.Worklog, dateFrom, dateTo string) error {
+	report, err := s.GetReport(worklog, dateFrom, dateTo)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(s.GetFilePath(constants.OutputFormatCsv))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+
+	err = writer.Write([]string{"Project", "Account id", "User", "Position", "Team", "Approval", "Issue", "Summary", "Work type",
+		"Date", "Hours", "Unrounded hours", "Rate", "Cost", "Logged hours", "Billable hours", "Billable cost"})
+	if err != nil {
+		return err
+	}
+
+	for _, project := range report.Projects {
+		for _, user := range project.Users {
+			for _, issue := range user.Issues {
+				for _, day := range issue.Days {
+					err = writer.Write([]string{
+						project.Key,
+						user.AccountId,
+						user.Name,
+						user.Position,
+						user.Team,
+						user.Approval,
+						issue.Key,
+						issue.Summary,
+						issue.WorkType,
+						day.Date,
+						s.formatFloat(day.Hours),
+						s.formatFloat(day.UnroundedHours),
+						strconv.Itoa(issue.Rate),
+						s.formatFloat(day.Cost),
+						s.formatFloat(day.LoggedHours),
+						s.formatFloat(day.BillableHours),
+						s.formatFloat(s.pricingService.roundCost(day.BillableHours * float64(issue.Rate))),
+					})
+					if err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+// GetReport prices the worklog by the pricing service of the Excel report, so hours and costs of issues, users
+// and projects are the same as in the detail sheet. Days keep hours of efforts as they are.
+func (s *ExportService) GetReport(worklog *models.Worklog, dateFrom, dateTo string) (*models.ExportReport, error) {
+	periods, err := s.pricingService.getPeriods(dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+	dateToPeriod := s.pricingService.getDateToPeriod(periods)
+
+	report := &models.ExportReport{DateFrom: dateFrom, DateTo: dateTo, By: worklog.By, Projects: []models.ExportProject{}}
+
+	for _, project := range worklog.Projects {
+		exportProject := models.ExportProject{Key: project.Key, Users: []models.ExportUser{}}
+
+		for _, user := range project.Users {
+			exportUser, err := s.getUser(user, project.Rounding, dateToPeriod)
+			if err != nil {
+				return nil, err
+			}
+
+			exportProject.Users = append(exportProject.Users, *exportUser)
+			exportProject.Hours = s.pricingService.roundHours(exportProject.Hours + exportUser.Hours)
+			exportProject.Cost = s.pricingService.roundCost(exportProject.Cost + exportUser.Cost)
+		}
+
+		report.Projects = append(report.Projects, exportProject)
+		report.Hours = s.pricingService.roundHours(report.Hours + exportProject.Hours)
+		report.Cost = s.pricingService.roundCost(report.Cost + exportProject.Cost)
+	}
+
+	return report, nil
+}
+
+func (s *ExportService) getUser(user models.User, rounding models.RoundingAppConfig, dateToPeriod map[string]int) (*models.ExportUser, error) {
+	userPrice, err := s.pricingService.getUserPrice(user, rounding, dateToPeriod)
+	if err != nil {
+		return nil, err
+	}
+
+	exportUser := &models.ExportUser{
+		AccountId: user.AccountId,
+		Name:      user.DisplayName,
+		Position:  user.Position,
+		Team:      user.Team,
+		Approval:  user.Approval,
+		Rate:      user.Rate,
+		Hours:     userPrice.Hours,
+		Cost:      s.pricingService.roundCost(userPrice.Cost),
+		Issues:    []models.ExportIssue{},
+	}
+
+	for i, issue := range user.Issues {
+		issuePrice := userPrice.Issues[i]
+
+		exportIssue := models.ExportIssue{
+			Key:           issue.Key,
+			Summary:       issue.Summary,
+			Type:          issue.Type,
+			EpicKey:       issue.EpicKey,
+			EpicSummary:   issue.EpicSummary,
+			Attribute:     issue.Attribute,
+			WorkType:      issue.WorkType,
+			Unapproved:    issue.Unapproved,
+			Fields:        issue.Fields,
+			Rate:          issuePrice.Rate,
+			Hours:         issuePrice.Hours,
+			Cost:          s.pricingService.roundCost(issuePrice.Cost),
+			LoggedHours:   issuePrice.LoggedHours,
+			BillableHours: issuePrice.BillableHours,
+			BillableCost:  s.pricingService.roundCost(issuePrice.BillableCost),
+			Days:          []models.ExportDay{},
+		}
+
+		for _, effort := range issue.Efforts {
+			day := models.ExportDay{
+				Date:           effort.Date,
+				Hours:          s.pricingService.convertSecondsToHours(effort.TimeSpentSeconds),
+				UnroundedHours: s.pricingService.convertSecondsToHours(effort.UnroundedSeconds),
+				LoggedHours:    s.pricingService.convertSecondsToHours(effort.LoggedSeconds),
+				BillableHours:  s.pricingService.convertSecondsToHours(effort.BillableSeconds),
+			}
+			day.Cost = s.pricingService.roundCost(day.Hours * float64(issuePrice.Rate))
+
+			for _, entry := range effort.Entries {
+				day.Entries = append(day.Entries, models.ExportEntry{
+					StartTime:   entry.StartTime,
+					Hours:       s.pricingService.convertSecondsToHours(entry.TimeSpentSeconds),
+					Description: entry.Description,
+				})
+			}
+
+			exportIssue.Days = append(exportIssue.Days, day)
+		}
+
+		exportUser.Issues = append(exportUser.Issues, exportIssue)
+	}
+
+	return exportUser, nil
+}
+
+func (s *ExportService) formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
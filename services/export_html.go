@@ -0,0 +1,260 @@
+package services
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"strconv"
+	"strings"
+	"tempo-worklog/constants"
+	"tempo-worklog/models"
+	"time"
+)
+
+// htmlReportTemplate is a single static page, sections are collapsed by details elements without scripts.
+const htmlReportTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { font-family: {{.Theme.FontFamily}}, sans-serif; color: {{.Theme.FontColor}}; margin: 0; padding: 8px; }
+h1 { font-size: 1.3em; margin: 4px 0 12px; }
+summary { cursor: pointer; padding: 8px; margin-top: 4px; }
+summary span { float: right; font-weight: normal; }
+details.project > summary { background: {{.Theme.ProjectColor}}; font-weight: bold; font-size: 1.1em; }
+details.user > summary { background: {{.Theme.UserColor}}; font-weight: bold; }
+details.user { margin-left: 8px; }
+.scroll { overflow-x: auto; }
+table { border-collapse: collapse; font-size: 0.9em; }
+th, td { border: 1px solid #d0d7de; padding: 3px 5px; text-align: right; white-space: nowrap; }
+th { background: {{.Theme.HeaderColor}}; color: {{.Theme.HeaderFontColor}}; }
+th.weekend, td.weekend { background-color: {{.Theme.WeekendColor}}; }
+td.task { text-align: left; white-space: normal; min-width: 200px; position: sticky; left: 0; background: #ffffff; }
+td.day { min-width: 32px; }
+tr.total td { font-weight: bold; background-color: {{.Theme.TeamColor}}; }
+tr.total td.task { background-color: {{.Theme.TeamColor}}; }
+td.heat { background-color: rgba({{.HeatColor}}, var(--heat)); }
+ul.entries { margin: 4px 0; padding-left: 16px; font-size: 0.9em; color: #57606a; }
+.total { background: {{.Theme.TotalColor}}; font-weight: bold; padding: 8px; margin-top: 8px; }
+.total span { float: right; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{range .Projects}}
+<details class="project" open>
+<summary>{{.Key}} <span>{{.Hours}} h · {{.Cost}}</span></summary>
+{{range .Users}}
+<details class="user">
+<summary>{{.Name}}{{if .Position}}, {{.Position}}{{end}}{{if .Team}} ({{.Team}}){{end}}{{if .Approval}} · {{.Approval}}{{end}} <span>{{.Hours}} h · {{.Cost}}</span></summary>
+<div class="scroll">
+<table>
+<tr><th>Task</th><th>Rate</th><th>Hours</th><th>Cost</th>{{range $.Days}}<th class="{{if .IsWeekend}}weekend{{end}}">{{.Weekday}}<br>{{.Label}}</th>{{end}}</tr>
+{{range .Issues}}
+<tr>
+<td class="task"><details><summary>{{.Task}}</summary><ul class="entries">{{range .Entries}}<li>{{.Date}}{{if .StartTime}} {{.StartTime}}{{end}} · {{.Hours}} h{{if .Description}} · {{.Description}}{{end}}</li>{{end}}</ul></details></td>
+<td>{{.Rate}}</td><td>{{.Hours}}</td><td>{{.Cost}}</td>
+{{range .Cells}}<td class="day{{if .IsWeekend}} weekend{{end}}{{if .Hours}} heat{{end}}"{{if .Hours}} style="--heat: {{.Heat}}"{{end}}>{{.Hours}}</td>{{end}}
+</tr>
+{{end}}
+<tr class="total"><td class="task">Total</td><td>{{.Rate}}</td><td>{{.Hours}}</td><td>{{.Cost}}</td>{{range .DayTotals}}<td class="day{{if .IsWeekend}} weekend{{end}}">{{.Hours}}</td>{{end}}</tr>
+</table>
+</div>
+</details>
+{{end}}
+</details>
+{{end}}
+<div class="total">Total <span>{{.Hours}} h · {{.Cost}}</span></div>
+</body>
+</html>
+`
+
+// SaveHtml writes priced worklog as single static page readable on phones.
+func (s *ExportService) SaveHtml(worklog *models.Worklog, dateFrom, dateTo string) error {
+	report, err := s.GetReport(worklog, dateFrom, dateTo)
+	if err != nil {
+		return err
+	}
+
+	htmlReport, err := s.getHtmlReport(report)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := template.New("report").Parse(htmlReportTemplate)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(s.GetFilePath(constants.OutputFormatHtml))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return tmpl.Execute(file, htmlReport)
+}
+
+// getHtmlReport formats the report for display, heat of day cells is relative to the busiest issue day of the report.
+func (s *ExportService) getHtmlReport(report *models.ExportReport) (*models.HtmlReport, error) {
+	days, dateToIndex, err := s.getHtmlDays(report.DateFrom, report.DateTo)
+	if err != nil {
+		return nil, err
+	}
+
+	maxHours := 0.0
+	for _, project := range report.Projects {
+		for _, user := range project.Users {
+			for _, issue := range user.Issues {
+				for _, day := range issue.Days {
+					if day.Hours > maxHours {
+						maxHours = day.Hours
+					}
+				}
+			}
+		}
+	}
+
+	htmlReport := &models.HtmlReport{
+		Title:     report.DateFrom + " – " + report.DateTo,
+		Theme:     s.theme,
+		Days:      days,
+		Hours:     s.formatFloat(report.Hours),
+		Cost:      s.formatCost(report.Cost),
+		HeatColor: s.getRgb(s.theme.HeaderColor),
+	}
+
+	for _, project := range report.Projects {
+		htmlProject := models.HtmlProject{Key: project.Key, Hours: s.formatFloat(project.Hours), Cost: s.formatCost(project.Cost)}
+
+		for _, user := range project.Users {
+			htmlUser := models.HtmlUser{
+				Name:     user.Name,
+				Position: user.Position,
+				Team:     user.Team,
+				Approval: user.Approval,
+				Rate:     user.Rate,
+				Hours:    s.formatFloat(user.Hours),
+				Cost:     s.formatCost(user.Cost),
+			}
+			dayTotals := make([]float64, len(days))
+
+			for _, issue := range user.Issues {
+				task := issue.Key + ": " + issue.Summary
+				if len(issue.WorkType) > 0 {
+					task += " (" + issue.WorkType + ")"
+				}
+				if issue.Unapproved {
+					task += " " + ReportUnapprovedLabel
+				}
+
+				htmlIssue := models.HtmlIssue{Task: task, Rate: issue.Rate, Hours: s.formatFloat(issue.Hours), Cost: s.formatCost(issue.Cost)}
+				dayHours := make([]float64, len(days))
+
+				for _, day := range issue.Days {
+					index, ok := dateToIndex[day.Date]
+					if !ok {
+						continue
+					}
+					dayHours[index] += day.Hours
+					dayTotals[index] = s.pricingService.roundHours(dayTotals[index] + day.Hours)
+
+					for _, entry := range day.Entries {
+						htmlIssue.Entries = append(htmlIssue.Entries, models.HtmlEntry{
+							Date:        day.Date,
+							StartTime:   s.formatStartTime(entry.StartTime),
+							Hours:       s.formatFloat(entry.Hours),
+							Description: entry.Description,
+						})
+					}
+				}
+
+				htmlIssue.Cells = s.getHtmlCells(dayHours, days, maxHours)
+				htmlUser.Issues = append(htmlUser.Issues, htmlIssue)
+			}
+
+			htmlUser.DayTotals = s.getHtmlCells(dayTotals, days, 0)
+			htmlProject.Users = append(htmlProject.Users, htmlUser)
+		}
+
+		htmlReport.Projects = append(htmlReport.Projects, htmlProject)
+	}
+
+	return htmlReport, nil
+}
+
+func (s *ExportService) getHtmlDays(dateFrom, dateTo string) ([]models.HtmlDay, map[string]int, error) {
+	startDate, err := time.Parse(constants.InputDateFormat, dateFrom)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	endDate, err := time.Parse(constants.InputDateFormat, dateTo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var days []models.HtmlDay
+	dateToIndex := map[string]int{}
+
+	for date := startDate; !date.After(endDate); date = date.AddDate(0, 0, 1) {
+		dateToIndex[date.Format(constants.InputDateFormat)] = len(days)
+		days = append(days, models.HtmlDay{
+			Label:     strconv.Itoa(date.Day()),
+			Weekday:   date.Weekday().String()[:2],
+			IsWeekend: date.Weekday() == time.Saturday || date.Weekday() == time.Sunday,
+		})
+	}
+
+	return days, dateToIndex, nil
+}
+
+// getHtmlCells formats hours of days, cells are not heated if max hours is zero.
+func (s *ExportService) getHtmlCells(hours []float64, days []models.HtmlDay, maxHours float64) []models.HtmlCell {
+	cells := make([]models.HtmlCell, len(hours))
+
+	for i, value := range hours {
+		cells[i].IsWeekend = days[i].IsWeekend
+		if value == 0 {
+			continue
+		}
+
+		cells[i].Hours = s.formatFloat(s.pricingService.roundHours(value))
+		if maxHours > 0 {
+			cells[i].Heat = s.pricingService.roundHours(0.15 + 0.85*value/maxHours)
+		}
+	}
+
+	return cells
+}
+
+// getRgb converts hex color of the theme, e.g. #2487bc or #28b, to comma separated red, green and blue,
+// invalid colors fall back to the default header color.
+func (s *ExportService) getRgb(color string) string {
+	hex := strings.TrimPrefix(color, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 6 {
+		return "36, 135, 188"
+	}
+
+	return fmt.Sprintf("%d, %d, %d", value>>16, value>>8&0xff, value&0xff)
+}
+
+func (s *ExportService) formatCost(cost float64) string {
+	return fmt.Sprintf("%.2f", cost)
+}
+
+// formatStartTime cuts seconds of Tempo start time, e.g. 09:30:00.
+func (s *ExportService) formatStartTime(startTime string) string {
+	if strings.Count(startTime, ":") == 2 {
+		return startTime[:strings.LastIndex(startTime, ":")]
+	}
+
+	return startTime
+}
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"tempo-worklog/constants"
+	"tempo-worklog/models"
+	"testing"
+)
+
+func TestGetReportMatchesDetailSheet(t *testing.T) {
+	for _, granularity := range []string{constants.GranularityDay, constants.GranularityWeek, constants.GranularityMonth} {
+		t.Run(granularity, func(t *testing.T) {
+			worklog := newTestWorklog("PRJ")
+			worklog.Projects[0].Users[0].Issues[0].Efforts = []models.Effort{
+				{Date: "2023-01-02", TimeSpentSeconds: 1000},
+				{Date: "2023-01-03", TimeSpentSeconds: 1000},
+				{Date: "2023-01-04", TimeSpentSeconds: 1000},
+			}
+
+			f := saveTestReport(t, models.ReportAppConfig{Granularity: granularity}, worklog)
+			sheet := f.GetSheetList()[1]
+			row := strconv.Itoa(findRow(t, f, sheet, "John Smith"))
+
+			report, err := NewExportService("report.xlsx", models.ReportThemeAppConfig{}, NewPricingService(granularity)).GetReport(worklog, "2023-01-02", "2023-01-08")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			user := report.Projects[0].Users[0]
+			if user.Hours != 0.84 {
+				t.Errorf("exported user hours are %v, want %v", user.Hours, 0.84)
+			}
+
+			for cell, value := range map[string]float64{"E" + row: user.Hours, "F" + row: user.Cost} {
+				if want := getCell(t, f, sheet, cell); strconv.FormatFloat(value, 'f', -1, 64) != want {
+					t.Errorf("exported value of %s is %v, want %s", cell, value, want)
+				}
+			}
+
+			if report.Hours != user.Hours || report.Cost != user.Cost {
+				t.Errorf("exported report has %v h and cost %v, want %v h and cost %v", report.Hours, report.Cost, user.Hours, user.Cost)
+			}
+		})
+	}
+}
+
+func TestSaveCsv(t *testing.T) {
+	reportFilePath := filepath.Join(t.TempDir(), "report.xlsx")
+	exportService := NewExportService(reportFilePath, models.ReportThemeAppConfig{}, NewPricingService(constants.GranularityDay))
+
+	err := exportService.SaveCsv(newTestWorklog("PRJ"), "2023-01-02", "2023-01-08")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := os.Open(exportService.GetFilePath(constants.OutputFormatCsv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"PRJ", "1", "John Smith", "", "", "", "PRJ-1", "Task", "", "2023-01-02", "1", "1", "10", "10", "1", "1", "10"}
+	if len(rows) != 2 || !reflect.DeepEqual(rows[1], want) {
+		t.Errorf("csv rows are %q, want header and %q", rows, want)
+	}
+}
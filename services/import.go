@@ -0,0 +1,334 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/xuri/excelize/v2"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"tempo-worklog/constants"
+	"tempo-worklog/models"
+	"time"
+	"unicode/utf8"
+)
+
+type ImportService struct {
+	config               models.ImportAppConfig
+	worklogService       *WorklogService
+	projectConfigService *ProjectConfigService
+}
+
+// NewImportService creates the service converting imported rows to worklog by the same code as worklog fetched from Tempo.
+func NewImportService(config models.ImportAppConfig, worklogService *WorklogService, projectConfigService *ProjectConfigService) *ImportService {
+	return &ImportService{config: config, worklogService: worklogService, projectConfigService: projectConfigService}
+}
+
+// CheckConfig rejects options which need Jira or Tempo and would change the time or cost of the report,
+// options which only describe the worklog are warned about and left empty.
+func (s *ImportService) CheckConfig(appConfig *models.AppConfig, jql string) error {
+	switch {
+	case len(jql) > 0:
+		return errors.New("JQL filter is not supported by import")
+	case appConfig.Report.Approvals || appConfig.Report.ApprovedOnly:
+		return errors.New("timesheet approvals are not supported by import")
+	case appConfig.Report.Plans:
+		return errors.New("plans are not supported by import")
+	case len(appConfig.Attributes.Filter) > 0:
+		return errors.New("work attribute filter is not supported by import")
+	case len(appConfig.Attributes.WorkTypeRates) > 0:
+		return errors.New("work type rates are not supported by import")
+	}
+
+	for projectKey, projectConfig := range appConfig.Projects {
+		if len(projectConfig.Jql) > 0 {
+			return errors.New("JQL filter of " + projectKey + " project is not supported by import")
+		}
+	}
+
+	if appConfig.Report.Epics {
+		log.Println("Warning: epics are not supported by import, issues are shown without epic")
+	}
+	if len(appConfig.Report.GroupBy) > 0 {
+		log.Println("Warning: work attributes are not supported by import, issues are shown without", appConfig.Report.GroupBy)
+	}
+	if len(appConfig.ReportLayout.IssueFields) > 0 {
+		log.Println("Warning: Jira issue fields are not supported by import, their columns are empty")
+	}
+	if appConfig.Report.Teams && appConfig.Report.TeamsSource == constants.TeamsSourceTempo {
+		log.Println("Warning: Tempo teams are not supported by import, only teams of project config are shown")
+	}
+
+	return nil
+}
+
+// GetWorklog reads worklog of the projects or users within the dates from CSV or XLSX export,
+// users are priced by project config like worklog fetched from Tempo.
+func (s *ImportService) GetWorklog(filePath, by string, keys []string, dateFrom, dateTo string) (*models.Worklog, error) {
+	projectConfigWrapper, err := s.projectConfigService.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.readRows(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	results, issueKeyToFields, err := s.getResults(rows, by, keys, dateFrom, dateTo)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Println("Imported", filePath+":", len(results), "of", len(rows)-1, "records")
+
+	projectKeyToResults := map[string][]models.TempoResult{} // group by project key
+	for _, result := range results {
+		projectKey := s.getProjectKey(result.Issue.Key)
+		projectKeyToResults[projectKey] = append(projectKeyToResults[projectKey], result)
+	}
+
+	// projects of project report keep order of input args like worklog fetched from Tempo
+	projectKeys := keys
+	if by == constants.ReportByUser {
+		projectKeys = make([]string, 0, len(projectKeyToResults))
+		for projectKey := range projectKeyToResults {
+			projectKeys = append(projectKeys, projectKey)
+		}
+		sort.Strings(projectKeys)
+	}
+
+	var projects []models.Project
+
+	for _, projectKey := range projectKeys {
+		projectConfig := projectConfigWrapper.ProjectKeyToConfig[projectKey]
+
+		users, err := s.worklogService.getUsers(projectKeyToResults[strings.ToUpper(projectKey)], &projectConfig,
+			map[string]string{}, issueKeyToFields, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		projects = append(projects, models.Project{Key: projectKey, Users: users})
+	}
+
+	worklog := &models.Worklog{By: by, Projects: projects}
+
+	err = s.projectConfigService.Save(projectConfigWrapper, worklog)
+	if err != nil {
+		return nil, err
+	}
+
+	return worklog, nil
+}
+
+// readRows reads all rows of the file including the header.
+func (s *ImportService) readRows(filePath string) ([][]string, error) {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".csv":
+		file, err := os.Open(filePath)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		delimiter := ','
+		if len(s.config.Delimiter) > 0 {
+			delimiter, _ = utf8.DecodeRuneInString(s.config.Delimiter)
+		}
+
+		reader := csv.NewReader(file)
+		reader.Comma = delimiter
+		reader.FieldsPerRecord = -1
+		reader.LazyQuotes = true
+
+		rows, err := reader.ReadAll()
+		if err != nil {
+			return nil, err
+		}
+
+		// exports of Excel start with byte order mark
+		if len(rows) > 0 && len(rows[0]) > 0 {
+			rows[0][0] = strings.TrimPrefix(rows[0][0], "\ufeff")
+		}
+
+		return rows, nil
+	case ".xlsx":
+		f, err := excelize.OpenFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		sheet := s.config.Sheet
+		if len(sheet) == 0 {
+			sheet = f.GetSheetName(0)
+		}
+
+		rows, err := f.GetRows(sheet)
+		if err != nil {
+			return nil, err
+		}
+
+		err = f.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		return rows, nil
+	default:
+		return nil, errors.New("unsupported import file: " + filePath)
+	}
+}
+
+// getResults converts rows to Tempo results of the projects or users within the dates,
+// summaries of issues are returned as their Jira fields.
+func (s *ImportService) getResults(rows [][]string, by string, keys []string,
+	dateFrom, dateTo string) ([]models.TempoResult, map[string]models.JiraSearchIssueFields, error) {
+	if len(rows) == 0 {
+		return nil, nil, errors.New("import file is empty")
+	}
+
+	columnToIndex, err := s.getColumnToIndex(rows[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var results []models.TempoResult
+	issueKeyToFields := map[string]models.JiraSearchIssueFields{}
+
+	for i, row := range rows[1:] {
+		rowNumber := i + 2 // header is the first row
+		issueKey := s.getValue(row, columnToIndex, s.config.Columns.Issue)
+		if len(issueKey) == 0 {
+			continue
+		}
+
+		date, err := time.Parse(s.config.DateFormat, s.getValue(row, columnToIndex, s.config.Columns.Date))
+		if err != nil {
+			return nil, nil, s.getRowError(rowNumber, s.config.Columns.Date, err)
+		}
+
+		startDate := date.Format(constants.InputDateFormat)
+		if startDate < dateFrom || startDate > dateTo {
+			continue
+		}
+
+		displayName := s.getValue(row, columnToIndex, s.config.Columns.User)
+		accountId := s.getValue(row, columnToIndex, s.config.Columns.AccountId)
+		if len(accountId) == 0 {
+			accountId = displayName
+		}
+
+		switch by {
+		case constants.ReportByUser:
+			if !s.containsKey(keys, accountId) && !s.containsKey(keys, displayName) {
+				continue
+			}
+		default:
+			if !s.containsKey(keys, s.getProjectKey(issueKey)) {
+				continue
+			}
+		}
+
+		timeSpentSeconds, err := s.getSeconds(s.getValue(row, columnToIndex, s.config.Columns.Hours))
+		if err != nil {
+			return nil, nil, s.getRowError(rowNumber, s.config.Columns.Hours, err)
+		}
+
+		// all time is billable without billable hours column, e.g. in Jira export
+		billableSeconds := timeSpentSeconds
+		if _, ok := columnToIndex[s.config.Columns.BillableHours]; ok {
+			billableSeconds, err = s.getSeconds(s.getValue(row, columnToIndex, s.config.Columns.BillableHours))
+			if err != nil {
+				return nil, nil, s.getRowError(rowNumber, s.config.Columns.BillableHours, err)
+			}
+		}
+
+		// time of date column is taken as start time if there is no start time
+		startTime := s.getValue(row, columnToIndex, s.config.Columns.StartTime)
+		if len(startTime) == 0 && (date.Hour() > 0 || date.Minute() > 0) {
+			startTime = date.Format("15:04:05")
+		}
+
+		if summary := s.getValue(row, columnToIndex, s.config.Columns.Summary); len(summary) > 0 {
+			issueKeyToFields[issueKey] = models.JiraSearchIssueFields{Summary: summary}
+		}
+
+		results = append(results, models.TempoResult{
+			Author:           models.TempoAuthor{AccountId: accountId, DisplayName: displayName},
+			Issue:            models.TempoIssue{Key: issueKey},
+			StartDate:        startDate,
+			StartTime:        startTime,
+			Description:      s.getValue(row, columnToIndex, s.config.Columns.Description),
+			TimeSpentSeconds: timeSpentSeconds,
+			BillableSeconds:  billableSeconds,
+		})
+	}
+
+	return results, issueKeyToFields, nil
+}
+
+// getColumnToIndex maps header names to column indexes, issue, user, date and hours columns are required,
+// other columns are read if present.
+func (s *ImportService) getColumnToIndex(header []string) (map[string]int, error) {
+	columnToIndex := map[string]int{}
+	for i, name := range header {
+		columnToIndex[strings.TrimSpace(name)] = i
+	}
+
+	columns := s.config.Columns
+	for _, column := range []string{columns.Issue, columns.User, columns.Date, columns.Hours} {
+		if _, ok := columnToIndex[column]; !ok {
+			return nil, errors.New("import column not found: " + column)
+		}
+	}
+
+	return columnToIndex, nil
+}
+
+func (s *ImportService) getValue(row []string, columnToIndex map[string]int, column string) string {
+	index, ok := columnToIndex[column]
+	if !ok || index >= len(row) {
+		return ""
+	}
+
+	return strings.TrimSpace(row[index])
+}
+
+// getSeconds parses hours with dot or comma as decimal separator.
+func (s *ImportService) getSeconds(hours string) (int, error) {
+	if len(hours) == 0 {
+		return 0, nil
+	}
+
+	value, err := strconv.ParseFloat(strings.Replace(hours, ",", ".", 1), 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(math.Round(value * 3600)), nil
+}
+
+func (s *ImportService) containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getProjectKey takes project key from issue key like worklog fetched from Tempo, keys of the file may be in any case.
+func (s *ImportService) getProjectKey(issueKey string) string {
+	return strings.ToUpper(s.worklogService.getProjectKey(issueKey))
+}
+
+func (s *ImportService) getRowError(rowNumber int, column string, err error) error {
+	return fmt.Errorf("row %d, column %s: %w", rowNumber, column, err)
+}
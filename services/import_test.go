@@ -0,0 +1,233 @@
+package services
+
+import (
+	"github.com/xuri/excelize/v2"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"tempo-worklog/models"
+	"testing"
+)
+
+func newTestImportConfig() models.ImportAppConfig {
+	return models.ImportAppConfig{
+		DateFormat: "2006-01-02 15:04",
+		Columns: models.ImportColumnsAppConfig{
+			Issue:         "Issue Key",
+			Summary:       "Issue summary",
+			User:          "Full name",
+			AccountId:     "Account ID",
+			Date:          "Work date",
+			Hours:         "Hours",
+			BillableHours: "Billed Hours",
+			Description:   "Work Description",
+		},
+	}
+}
+
+func TestGetSeconds(t *testing.T) {
+	tests := []struct {
+		hours   string
+		want    int
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"1", 3600, false},
+		{"1.5", 5400, false},
+		{"0,25", 900, false},
+		{"0.3333", 1200, false},
+		{"1h", 0, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.hours, func(t *testing.T) {
+			seconds, err := NewImportService(newTestImportConfig(), newTestWorklogService(t, nil), nil).getSeconds(test.hours)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("getSeconds() error = %v, want error: %v", err, test.wantErr)
+			}
+			if seconds != test.want {
+				t.Errorf("getSeconds() = %d, want %d", seconds, test.want)
+			}
+		})
+	}
+}
+
+func TestGetResults(t *testing.T) {
+	rows := [][]string{
+		{"Issue Key", "Issue summary", "Full name", "Account ID", "Work date", "Hours", "Billed Hours", "Work Description"},
+		{"PRJ-1", "Task", "John Smith", "1", "2023-01-02 09:30", "1,5", "1", "Design"},
+		{"OTH-1", "Other", "Jane Doe", "2", "2023-01-03 00:00", "2", "2", ""},
+		{"PRJ-2", "", "Jane Doe", "", "2023-01-09 00:00", "1", "1", ""},
+		{"", "", "", "", "", "", "", ""},
+	}
+
+	john := models.TempoResult{
+		Author:           models.TempoAuthor{AccountId: "1", DisplayName: "John Smith"},
+		Issue:            models.TempoIssue{Key: "PRJ-1"},
+		StartDate:        "2023-01-02",
+		StartTime:        "09:30:00",
+		Description:      "Design",
+		TimeSpentSeconds: 5400,
+		BillableSeconds:  3600,
+	}
+	jane := models.TempoResult{
+		Author:           models.TempoAuthor{AccountId: "2", DisplayName: "Jane Doe"},
+		Issue:            models.TempoIssue{Key: "OTH-1"},
+		StartDate:        "2023-01-03",
+		TimeSpentSeconds: 7200,
+		BillableSeconds:  7200,
+	}
+
+	tests := []struct {
+		name       string
+		by         string
+		keys       []string
+		dateTo     string
+		want       []models.TempoResult
+		wantFields map[string]models.JiraSearchIssueFields
+	}{
+		{"by project", "project", []string{"prj"}, "2023-01-08", []models.TempoResult{john}, map[string]models.JiraSearchIssueFields{"PRJ-1": {Summary: "Task"}}},
+		{"by user account id", "user", []string{"2"}, "2023-01-08", []models.TempoResult{jane}, map[string]models.JiraSearchIssueFields{"OTH-1": {Summary: "Other"}}},
+		{"by user name", "user", []string{"john smith"}, "2023-01-08", []models.TempoResult{john}, map[string]models.JiraSearchIssueFields{"PRJ-1": {Summary: "Task"}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			results, issueKeyToFields, err := NewImportService(newTestImportConfig(), newTestWorklogService(t, nil), nil).
+				getResults(rows, test.by, test.keys, "2023-01-02", test.dateTo)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(results, test.want) {
+				t.Errorf("getResults() = %+v, want %+v", results, test.want)
+			}
+			if !reflect.DeepEqual(issueKeyToFields, test.wantFields) {
+				t.Errorf("getResults() fields = %v, want %v", issueKeyToFields, test.wantFields)
+			}
+		})
+	}
+}
+
+func TestGetResultsErrors(t *testing.T) {
+	config := newTestImportConfig()
+
+	tests := []struct {
+		name    string
+		rows    [][]string
+		wantErr string
+	}{
+		{"empty", nil, "import file is empty"},
+		{"missing column", [][]string{{"Issue Key", "Full name", "Work date"}}, "import column not found: Hours"},
+		{"bad hours", [][]string{
+			{"Issue Key", "Full name", "Work date", "Hours"},
+			{"PRJ-1", "John Smith", "2023-01-02 00:00", "one"},
+		}, `row 2, column Hours: strconv.ParseFloat: parsing "one": invalid syntax`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, err := NewImportService(config, newTestWorklogService(t, nil), nil).getResults(test.rows, "project", []string{"PRJ"}, "2023-01-02", "2023-01-08")
+			if err == nil || err.Error() != test.wantErr {
+				t.Fatalf("getResults() error = %v, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadRows(t *testing.T) {
+	dir := t.TempDir()
+	want := [][]string{{"Issue Key", "Hours"}, {"PRJ-1", "1,5"}}
+
+	csvFilePath := filepath.Join(dir, "worklog.csv")
+	err := os.WriteFile(csvFilePath, []byte("\ufeffIssue Key;Hours\nPRJ-1;\"1,5\"\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	xlsxFilePath := filepath.Join(dir, "worklog.xlsx")
+	f := excelize.NewFile()
+	for i, row := range want {
+		err = f.SetSheetRow("Sheet1", "A"+strconv.Itoa(i+1), &row)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = f.SaveAs(xlsxFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	tests := []struct {
+		name     string
+		filePath string
+		wantErr  string
+	}{
+		{name: "csv", filePath: csvFilePath},
+		{name: "xlsx", filePath: xlsxFilePath},
+		{name: "unsupported", filePath: filepath.Join(dir, "worklog.xls"), wantErr: "unsupported import file: " + filepath.Join(dir, "worklog.xls")},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := newTestImportConfig()
+			config.Delimiter = ";"
+
+			rows, err := NewImportService(config, newTestWorklogService(t, nil), nil).readRows(test.filePath)
+			if len(test.wantErr) > 0 {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("readRows() error = %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(rows, want) {
+				t.Errorf("readRows() = %q, want %q", rows, want)
+			}
+		})
+	}
+}
+
+func TestCheckConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		appConfig models.AppConfig
+		jql       string
+		wantErr   string
+	}{
+		{name: "supported", appConfig: models.AppConfig{Report: models.ReportAppConfig{Epics: true}}},
+		{name: "jql", jql: "labels = client", wantErr: "JQL filter is not supported by import"},
+		{
+			name:      "approvals",
+			appConfig: models.AppConfig{Report: models.ReportAppConfig{ApprovedOnly: true}},
+			wantErr:   "timesheet approvals are not supported by import",
+		},
+		{
+			name:      "work type rates",
+			appConfig: models.AppConfig{Attributes: models.AttributesAppConfig{WorkTypeRates: map[string]int{"consulting": 50}}},
+			wantErr:   "work type rates are not supported by import",
+		},
+		{
+			name:      "project jql",
+			appConfig: models.AppConfig{Projects: map[string]models.ProjectAppConfig{"prj": {Jql: "labels = client"}}},
+			wantErr:   "JQL filter of prj project is not supported by import",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := NewImportService(newTestImportConfig(), newTestWorklogService(t, nil), nil).CheckConfig(&test.appConfig, test.jql)
+			if len(test.wantErr) == 0 && err != nil {
+				t.Fatal(err)
+			}
+			if len(test.wantErr) > 0 && (err == nil || err.Error() != test.wantErr) {
+				t.Fatalf("CheckConfig() error = %v, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"errors"
+	"flag"
 	"log"
 	"os"
 	"strings"
@@ -30,18 +31,14 @@ func (s *InputArgsService) Parse(args []string) (*models.InputArgs, error) {
 	}
 	log.Println("Validated config-file:", configFile)
 
-	// 2nd
-	rawProjects := args[1]
-	var projects []string
-	for _, project := range strings.Split(rawProjects, ",") {
-		if len(project) > 0 {
-			projects = append(projects, project)
+	// 2nd, projects or users depending on options
+	rawKeys := args[1]
+	var keys []string
+	for _, key := range strings.Split(rawKeys, ",") {
+		if len(key) > 0 {
+			keys = append(keys, key)
 		}
 	}
-	if len(projects) == 0 {
-		return nil, errors.New("projects are not set")
-	}
-	log.Println("Validated projects:", strings.Join(projects[:], ", "))
 
 	// 3rd
 	dateFrom := args[2]
@@ -59,11 +56,68 @@ func (s *InputArgsService) Parse(args []string) (*models.InputArgs, error) {
 	}
 	log.Println("Validated date-to:", dateTo)
 
+	// options
+	flags := flag.NewFlagSet("tempo-worklog", flag.ContinueOnError)
+	jql := flags.String("jql", "", "JQL filter of issues")
+	by := flags.String("by", constants.ReportByProject, "report by project or user")
+	importFile := flags.String("import", "", "CSV or XLSX worklog export read instead of Tempo API")
+	outputFormat := flags.String("output-format", constants.OutputFormatXlsx, "comma separated output formats: xlsx, json, csv, html")
+
+	err = flags.Parse(args[4:])
+	if err != nil {
+		return nil, err
+	}
+	if len(*jql) > 0 {
+		log.Println("Validated jql:", *jql)
+	}
+	if len(*importFile) > 0 {
+		_, err = os.Stat(*importFile)
+		if err != nil {
+			return nil, err
+		}
+		log.Println("Validated import file:", *importFile)
+	}
+
+	var outputFormats []string
+	for _, format := range strings.Split(*outputFormat, ",") {
+		switch format {
+		case constants.OutputFormatXlsx, constants.OutputFormatJson, constants.OutputFormatCsv, constants.OutputFormatHtml:
+			outputFormats = append(outputFormats, format)
+		case "":
+		default:
+			return nil, errors.New("unknown output format: " + format)
+		}
+	}
+	if len(outputFormats) == 0 {
+		return nil, errors.New("output formats are not set")
+	}
+	log.Println("Validated output formats:", strings.Join(outputFormats, ", "))
+
 	result := &models.InputArgs{
-		ConfigFile: configFile,
-		Projects:   projects,
-		DateFrom:   dateFrom,
-		DateTo:     dateTo,
+		ConfigFile:    configFile,
+		By:            *by,
+		DateFrom:      dateFrom,
+		DateTo:        dateTo,
+		Jql:           *jql,
+		ImportFile:    *importFile,
+		OutputFormats: outputFormats,
+	}
+
+	switch *by {
+	case constants.ReportByProject:
+		if len(keys) == 0 {
+			return nil, errors.New("projects are not set")
+		}
+		result.Projects = keys
+		log.Println("Validated projects:", strings.Join(keys, ", "))
+	case constants.ReportByUser:
+		if len(keys) == 0 {
+			return nil, errors.New("users are not set")
+		}
+		result.Users = keys
+		log.Println("Validated users:", strings.Join(keys, ", "))
+	default:
+		return nil, errors.New("unknown report by: " + *by)
 	}
 
 	return result, nil
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"tempo-worklog/models"
+	"testing"
+)
+
+func TestInputArgsParse(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "AppConfig.yaml")
+	err := os.WriteFile(configFile, nil, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    *models.InputArgs
+		wantErr string
+	}{
+		{
+			name: "projects",
+			args: []string{configFile, "PRJ,,OTH", "2023-01-02", "2023-01-08", "--jql", "labels = client"},
+			want: &models.InputArgs{ConfigFile: configFile, By: "project", Projects: []string{"PRJ", "OTH"},
+				DateFrom: "2023-01-02", DateTo: "2023-01-08", Jql: "labels = client", OutputFormats: []string{"xlsx"}},
+		},
+		{
+			name: "users",
+			args: []string{configFile, "123,team:Backend", "2023-01-02", "2023-01-08", "--by", "user", "--output-format", "json,,csv"},
+			want: &models.InputArgs{ConfigFile: configFile, By: "user", Users: []string{"123", "team:Backend"},
+				DateFrom: "2023-01-02", DateTo: "2023-01-08", OutputFormats: []string{"json", "csv"}},
+		},
+		{
+			name:    "no users",
+			args:    []string{configFile, ",", "2023-01-02", "2023-01-08", "--by", "user"},
+			wantErr: "users are not set",
+		},
+		{
+			name:    "unknown output format",
+			args:    []string{configFile, "PRJ", "2023-01-02", "2023-01-08", "--output-format", "xlsx,pdf"},
+			wantErr: "unknown output format: pdf",
+		},
+		{
+			name:    "unknown by",
+			args:    []string{configFile, "PRJ", "2023-01-02", "2023-01-08", "--by", "team"},
+			wantErr: "unknown report by: team",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			inputArgs, err := NewInputArgsService().Parse(test.args)
+			if len(test.wantErr) > 0 {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("Parse() error = %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(inputArgs, test.want) {
+				t.Errorf("Parse() = %+v, want %+v", inputArgs, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"tempo-worklog/constants"
+	"tempo-worklog/models"
+	"time"
+)
+
+// PricingService calculates hours and cost of worklog for the detail sheet, the summary and the exports alike:
+// hours of issues are summed up by report periods and rounded by issue scope, hours of users are rounded by total scope.
+type PricingService struct {
+	granularity string
+}
+
+func NewPricingService(granularity string) *PricingService {
+	return &PricingService{granularity: granularity}
+}
+
+// getPeriods splits report dates into periods of the granularity, the first and last periods are cut by the dates.
+func (s *PricingService) getPeriods(dateFrom, dateTo string) ([]models.ReportPeriod, error) {
+	startDate, err := time.Parse(constants.InputDateFormat, dateFrom)
+	if err != nil {
+		return nil, err
+	}
+
+	endDate, err := time.Parse(constants.InputDateFormat, dateTo)
+	if err != nil {
+		return nil, err
+	}
+
+	var periods []models.ReportPeriod
+
+	for date := startDate; !date.After(endDate); {
+		period, err := s.getPeriod(date, startDate, endDate)
+		if err != nil {
+			return nil, err
+		}
+		periods = append(periods, *period)
+
+		date = period.DateTo.AddDate(0, 0, 1)
+	}
+
+	return periods, nil
+}
+
+func (s *PricingService) getPeriod(date, startDate, endDate time.Time) (*models.ReportPeriod, error) {
+	var periodFrom, periodTo time.Time
+
+	switch s.granularity {
+	case constants.GranularityDay:
+		periodFrom = date
+		periodTo = date
+	case constants.GranularityWeek:
+		periodFrom = date.AddDate(0, 0, -((int(date.Weekday()) + ReportFirstDayOfWeekDiff) % ReportDaysInWeek))
+		periodTo = periodFrom.AddDate(0, 0, ReportDaysInWeek-1)
+	case constants.GranularityMonth:
+		periodFrom = time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+		periodTo = periodFrom.AddDate(0, 1, -1)
+	default:
+		return nil, errors.New("unknown report granularity: " + s.granularity)
+	}
+
+	// cut period by report range
+	if periodFrom.Before(startDate) {
+		periodFrom = startDate
+	}
+	if periodTo.After(endDate) {
+		periodTo = endDate
+	}
+
+	period := &models.ReportPeriod{DateFrom: periodFrom, DateTo: periodTo}
+
+	switch s.granularity {
+	case constants.GranularityDay:
+		period.Label = fmt.Sprintf(ColumnReportDateFormat, periodFrom.Month(), periodFrom.Day())
+		period.IsWeekend = s.isWeekendDate(periodFrom)
+	case constants.GranularityWeek:
+		period.Label = fmt.Sprintf(ColumnReportWeekFormat, periodFrom.Month(), periodFrom.Day(), periodTo.Month(), periodTo.Day())
+	case constants.GranularityMonth:
+		period.Label = periodFrom.Format(ColumnReportMonthFormat)
+	}
+
+	return period, nil
+}
+
+// isWeekend tells if the date of effort is Saturday or Sunday.
+func (s *PricingService) isWeekend(date string) bool {
+	effortDate, err := time.Parse(constants.InputDateFormat, date)
+	if err != nil {
+		return false
+	}
+
+	return s.isWeekendDate(effortDate)
+}
+
+func (s *PricingService) isWeekendDate(date time.Time) bool {
+	return date.Weekday() == time.Saturday || date.Weekday() == time.Sunday
+}
+
+// getDateToPeriod maps dates of the periods to period indexes.
+func (s *PricingService) getDateToPeriod(periods []models.ReportPeriod) map[string]int {
+	dateToPeriod := map[string]int{}
+
+	for i, period := range periods {
+		for date := period.DateFrom; !date.After(period.DateTo); date = date.AddDate(0, 0, 1) {
+			dateToPeriod[date.Format(constants.InputDateFormat)] = i
+		}
+	}
+
+	return dateToPeriod
+}
+
+// getUserPrice prices issues of the user and sums them up, user hours are sums of periods
+// or of rounded issue hours if issues are rounded.
+func (s *PricingService) getUserPrice(user models.User, rounding models.RoundingAppConfig,
+	dateToPeriod map[string]int) (*models.UserPrice, error) {
+	userPrice := &models.UserPrice{PeriodToHours: map[int]float64{}, WeekendPeriods: map[int]bool{}}
+	issuesHours := 0.0
+	issuesCost := 0.0
+
+	for _, issue := range user.Issues {
+		issuePrice, err := s.getIssuePrice(user, issue, rounding, dateToPeriod)
+		if err != nil {
+			return nil, err
+		}
+
+		userPrice.Issues = append(userPrice.Issues, *issuePrice)
+		issuesHours += issuePrice.Hours
+		issuesCost += issuePrice.Cost
+
+		userPrice.LoggedHours = s.roundHours(userPrice.LoggedHours + issuePrice.LoggedHours)
+		userPrice.BillableHours = s.roundHours(userPrice.BillableHours + issuePrice.BillableHours)
+		userPrice.BillableCost += issuePrice.BillableCost
+		for period, hours := range issuePrice.PeriodToHours {
+			userPrice.PeriodToHours[period] = s.roundHours(userPrice.PeriodToHours[period] + hours)
+		}
+		for period := range issuePrice.WeekendPeriods {
+			userPrice.WeekendPeriods[period] = true
+		}
+	}
+
+	issuesHours = s.roundHours(issuesHours)
+
+	userPrice.Hours = s.sumHours(userPrice.PeriodToHours)
+	if rounding.Mode != constants.RoundingModeNone && rounding.Scope == constants.RoundingScopeIssue {
+		userPrice.Hours = issuesHours
+	}
+	userPrice.Hours = s.getRoundedHours(userPrice.Hours, rounding, constants.RoundingScopeTotal)
+	userPrice.Cost = s.getUserCost(user, rounding, userPrice.Hours, issuesHours, issuesCost)
+
+	return userPrice, nil
+}
+
+// getIssuePrice aggregates issue efforts by report periods and rounds their sum by issue scope,
+// hours of each day are rounded before aggregation so that sums do not depend on the granularity.
+func (s *PricingService) getIssuePrice(user models.User, issue models.Issue, rounding models.RoundingAppConfig,
+	dateToPeriod map[string]int) (*models.IssuePrice, error) {
+	issuePrice := &models.IssuePrice{
+		Rate:           s.getIssueRate(user, issue),
+		PeriodToHours:  map[int]float64{},
+		WeekendPeriods: map[int]bool{},
+	}
+
+	for _, effort := range issue.Efforts {
+		period, ok := dateToPeriod[effort.Date]
+		if !ok {
+			return nil, errors.New("effort date is out of report range: " + effort.Date)
+		}
+
+		hours := s.convertSecondsToHours(effort.TimeSpentSeconds)
+		issuePrice.PeriodToHours[period] = s.roundHours(issuePrice.PeriodToHours[period] + hours)
+		issuePrice.LoggedHours = s.roundHours(issuePrice.LoggedHours + s.convertSecondsToHours(effort.LoggedSeconds))
+		issuePrice.BillableHours = s.roundHours(issuePrice.BillableHours + s.convertSecondsToHours(effort.BillableSeconds))
+
+		if hours > 0 && s.isWeekend(effort.Date) {
+			issuePrice.WeekendPeriods[period] = true
+		}
+	}
+
+	issuePrice.Hours = s.getRoundedHours(s.sumHours(issuePrice.PeriodToHours), rounding, constants.RoundingScopeIssue)
+	issuePrice.Cost = issuePrice.Hours * float64(issuePrice.Rate)
+	issuePrice.BillableCost = issuePrice.BillableHours * float64(issuePrice.Rate)
+
+	return issuePrice, nil
+}
+
+// getIssueRate returns rate override of work type of issue or rate of user, unapproved time is not billed.
+func (s *PricingService) getIssueRate(user models.User, issue models.Issue) int {
+	if issue.Unapproved {
+		return 0
+	}
+
+	if issue.Rate > 0 {
+		return issue.Rate
+	}
+
+	return user.Rate
+}
+
+// hasRateOverrides tells if cost of user is the sum of issue costs instead of hours by rate of user.
+func (s *PricingService) hasRateOverrides(user models.User) bool {
+	for _, issue := range user.Issues {
+		if s.getIssueRate(user, issue) != user.Rate {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getUserCost returns hours by rate of user or the sum of issue costs if issues override the rate,
+// the sum is scaled to user hours rounded by total scope so that cost follows the rounded hours.
+func (s *PricingService) getUserCost(user models.User, rounding models.RoundingAppConfig, hours, issuesHours, issuesCost float64) float64 {
+	if !s.hasRateOverrides(user) {
+		return hours * float64(user.Rate)
+	}
+
+	if s.isTotalRounding(rounding) && issuesHours > 0 {
+		return issuesCost * hours / issuesHours
+	}
+
+	return issuesCost
+}
+
+// isTotalRounding tells if hours of user are rounded as a whole.
+func (s *PricingService) isTotalRounding(rounding models.RoundingAppConfig) bool {
+	return rounding.Scope == constants.RoundingScopeTotal && rounding.Minutes > 0 &&
+		(rounding.Mode == constants.RoundingModeNearest || rounding.Mode == constants.RoundingModeUp)
+}
+
+// getRoundedHours applies rounding policy of the project to the hours of issue or total scope.
+func (s *PricingService) getRoundedHours(hours float64, rounding models.RoundingAppConfig, scope string) float64 {
+	if rounding.Scope != scope || rounding.Minutes <= 0 {
+		return hours
+	}
+
+	increments := hours * 60 / float64(rounding.Minutes)
+
+	switch rounding.Mode {
+	case constants.RoundingModeNearest:
+		increments = math.Round(increments)
+	case constants.RoundingModeUp:
+		increments = math.Ceil(math.Round(increments*1e6) / 1e6) // skip floating point errors of exact increments
+	default:
+		return hours
+	}
+
+	return s.roundHours(increments * float64(rounding.Minutes) / 60)
+}
+
+func (s *PricingService) sumHours(periodToHours map[int]float64) float64 {
+	hours := 0.0
+	for _, periodHours := range periodToHours {
+		hours += periodHours
+	}
+
+	return s.roundHours(hours)
+}
+
+func (s *PricingService) convertSecondsToHours(seconds int) float64 {
+	return s.roundHours(float64(seconds) / 3600)
+}
+
+func (s *PricingService) roundHours(hours float64) float64 {
+	return math.Round(hours*100) / 100
+}
+
+func (s *PricingService) roundCost(cost float64) float64 {
+	return math.Round(cost*100) / 100
+}
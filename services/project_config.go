@@ -100,9 +100,15 @@ func (s *ProjectConfigService) getUserConfigs(f *excelize.File, sheet string) (m
 			}
 		}
 
+		team := ""
+		if len(rowCols) > 3 {
+			team = rowCols[3]
+		}
+
 		userConfigs[userName] = models.UserConfig{
 			Position: position,
 			Rate:     rate,
+			Team:     team,
 		}
 	}
 
@@ -137,7 +143,7 @@ func (s *ProjectConfigService) updateProjectConfigs(projectConfigWrapper *models
 			updatedUserConfig := models.UserConfig{}
 
 			if userConfig, ok := userConfigs[worklogUser.DisplayName]; ok {
-				updatedUserConfig = models.UserConfig{Position: userConfig.Position, Rate: userConfig.Rate}
+				updatedUserConfig = models.UserConfig{Position: userConfig.Position, Rate: userConfig.Rate, Team: userConfig.Team}
 			}
 			updatedUserConfigs[worklogUser.DisplayName] = updatedUserConfig
 		}
@@ -214,6 +220,12 @@ func (s *ProjectConfigService) createProjectSheet(f *excelize.File, sheet string
 		return err
 	}
 
+	style, err = f.NewStyle(&excelize.Style{Font: &font})
+	err = f.SetColStyle(sheet, "D", style)
+	if err != nil {
+		return err
+	}
+
 	alignment := excelize.Alignment{Horizontal: "center", Vertical: "center"}
 	borders := []excelize.Border{
 		{Type: "top", Color: "#000000", Style: 1},
@@ -224,7 +236,7 @@ func (s *ProjectConfigService) createProjectSheet(f *excelize.File, sheet string
 	headerFont := excelize.Font{Size: 13, Color: "#ffffff", Bold: true}
 	fill := excelize.Fill{Color: []string{"#009a00"}, Type: "pattern", Pattern: 3}
 	style, err = f.NewStyle(&excelize.Style{Alignment: &alignment, Font: &headerFont, Border: borders, Fill: fill})
-	err = f.SetCellStyle(sheet, "A1", "C1", style)
+	err = f.SetCellStyle(sheet, "A1", "D1", style)
 	if err != nil {
 		return err
 	}
@@ -249,6 +261,11 @@ func (s *ProjectConfigService) createProjectSheet(f *excelize.File, sheet string
 		return err
 	}
 
+	err = f.SetColWidth(sheet, "D", "D", 30)
+	if err != nil {
+		return err
+	}
+
 	err = f.SetCellValue(sheet, "A1", "Name")
 	if err != nil {
 		return err
@@ -264,6 +281,11 @@ func (s *ProjectConfigService) createProjectSheet(f *excelize.File, sheet string
 		return err
 	}
 
+	err = f.SetCellValue(sheet, "D1", "Team")
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -309,6 +331,11 @@ func (s *ProjectConfigService) fillProjectSheetWithUsers(f *excelize.File, sheet
 		if err != nil {
 			return err
 		}
+
+		err = f.SetCellValue(sheet, "D"+rowIndex, users[userName].Team)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"math"
+	"strings"
+	"tempo-worklog/constants"
+	"tempo-worklog/models"
+)
+
+type RoundingService struct {
+	defaultConfig  models.RoundingAppConfig
+	projectConfigs map[string]models.ProjectAppConfig
+}
+
+func NewRoundingService(defaultConfig models.RoundingAppConfig, projectConfigs map[string]models.ProjectAppConfig) *RoundingService {
+	return &RoundingService{defaultConfig: defaultConfig, projectConfigs: projectConfigs}
+}
+
+// Apply rounds time spent of worklogs or days according to the project rounding policy,
+// rounding of issues and totals is left to the report as it depends on the sums.
+func (s *RoundingService) Apply(worklog *models.Worklog) error {
+	for i := range worklog.Projects {
+		project := &worklog.Projects[i]
+
+		config, err := s.getConfig(project.Key)
+		if err != nil {
+			return err
+		}
+		project.Rounding = *config
+
+		if config.Mode == constants.RoundingModeNone {
+			continue
+		}
+
+		log.Println("Rounding", project.Key, "project:", config.Mode, config.Minutes, "minutes per", config.Scope)
+
+		for _, user := range project.Users {
+			for _, issue := range user.Issues {
+				for j := range issue.Efforts {
+					effort := &issue.Efforts[j]
+
+					switch config.Scope {
+					case constants.RoundingScopeWorklog:
+						timeSpentSeconds := 0
+						for _, worklogSeconds := range effort.WorklogsSeconds {
+							timeSpentSeconds += s.roundSeconds(worklogSeconds, *config)
+						}
+						effort.TimeSpentSeconds = timeSpentSeconds
+					case constants.RoundingScopeDay:
+						effort.TimeSpentSeconds = s.roundSeconds(effort.TimeSpentSeconds, *config)
+					}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *RoundingService) getConfig(projectKey string) (*models.RoundingAppConfig, error) {
+	config := s.defaultConfig
+
+	// project options override the default ones
+	if projectConfig, ok := s.projectConfigs[strings.ToLower(projectKey)]; ok && len(projectConfig.Rounding.Mode) > 0 {
+		config.Mode = projectConfig.Rounding.Mode
+		if projectConfig.Rounding.Minutes > 0 {
+			config.Minutes = projectConfig.Rounding.Minutes
+		}
+		if len(projectConfig.Rounding.Scope) > 0 {
+			config.Scope = projectConfig.Rounding.Scope
+		}
+	}
+
+	switch config.Mode {
+	case constants.RoundingModeNone:
+		return &config, nil
+	case constants.RoundingModeNearest, constants.RoundingModeUp:
+	default:
+		return nil, errors.New("unknown rounding mode of project " + projectKey + ": " + config.Mode)
+	}
+
+	switch config.Scope {
+	case constants.RoundingScopeWorklog, constants.RoundingScopeDay, constants.RoundingScopeIssue, constants.RoundingScopeTotal:
+	default:
+		return nil, errors.New("unknown rounding scope of project " + projectKey + ": " + config.Scope)
+	}
+
+	if config.Minutes <= 0 {
+		return nil, errors.New("rounding minutes must be positive for project " + projectKey)
+	}
+
+	return &config, nil
+}
+
+// roundSeconds rounds seconds to the increment of configured minutes.
+func (s *RoundingService) roundSeconds(seconds int, config models.RoundingAppConfig) int {
+	increment := float64(config.Minutes * 60)
+
+	switch config.Mode {
+	case constants.RoundingModeNearest:
+		return int(math.Round(float64(seconds)/increment) * increment)
+	case constants.RoundingModeUp:
+		return int(math.Ceil(float64(seconds)/increment) * increment)
+	default:
+		return seconds
+	}
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"reflect"
+	"tempo-worklog/models"
+	"testing"
+)
+
+func TestRoundingApply(t *testing.T) {
+	tests := []struct {
+		name           string
+		defaultConfig  models.RoundingAppConfig
+		projectConfigs map[string]models.ProjectAppConfig
+		want           []int
+	}{
+		{
+			"none",
+			models.RoundingAppConfig{Mode: "none", Scope: "day"},
+			nil,
+			[]int{1500, 2000},
+		},
+		{
+			"nearest per day",
+			models.RoundingAppConfig{Mode: "nearest", Minutes: 15, Scope: "day"},
+			nil,
+			[]int{1800, 1800},
+		},
+		{
+			"up per worklog",
+			models.RoundingAppConfig{Mode: "up", Minutes: 15, Scope: "worklog"},
+			nil,
+			[]int{1800, 3600},
+		},
+		{
+			"issue scope is left to report",
+			models.RoundingAppConfig{Mode: "up", Minutes: 15, Scope: "issue"},
+			nil,
+			[]int{1500, 2000},
+		},
+		{
+			"project override",
+			models.RoundingAppConfig{Mode: "none", Scope: "day"},
+			map[string]models.ProjectAppConfig{"prj": {Rounding: models.RoundingAppConfig{Mode: "up", Minutes: 60}}},
+			[]int{3600, 3600},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			worklog := &models.Worklog{Projects: []models.Project{{
+				Key: "PRJ",
+				Users: []models.User{{Issues: []models.Issue{{Efforts: []models.Effort{
+					{Date: "2023-01-02", TimeSpentSeconds: 1500, UnroundedSeconds: 1500, WorklogsSeconds: []int{1500}},
+					{Date: "2023-01-03", TimeSpentSeconds: 2000, UnroundedSeconds: 2000, WorklogsSeconds: []int{1000, 1000}},
+				}}}}},
+			}}}
+
+			err := NewRoundingService(test.defaultConfig, test.projectConfigs).Apply(worklog)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []int
+			for _, effort := range worklog.Projects[0].Users[0].Issues[0].Efforts {
+				got = append(got, effort.TimeSpentSeconds)
+				if effort.UnroundedSeconds != 1500 && effort.UnroundedSeconds != 2000 {
+					t.Errorf("unrounded seconds changed to %d", effort.UnroundedSeconds)
+				}
+			}
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("seconds are %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRoundingGetConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  models.RoundingAppConfig
+		wantErr string
+	}{
+		{"none needs no minutes", models.RoundingAppConfig{Mode: "none"}, ""},
+		{"valid", models.RoundingAppConfig{Mode: "nearest", Minutes: 6, Scope: "total"}, ""},
+		{"unknown mode", models.RoundingAppConfig{Mode: "down", Minutes: 6, Scope: "day"}, "unknown rounding mode of project PRJ: down"},
+		{"unknown scope", models.RoundingAppConfig{Mode: "up", Minutes: 6, Scope: "week"}, "unknown rounding scope of project PRJ: week"},
+		{"no minutes", models.RoundingAppConfig{Mode: "up", Scope: "day"}, "rounding minutes must be positive for project PRJ"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewRoundingService(test.config, nil).getConfig("PRJ")
+
+			if len(test.wantErr) == 0 && err != nil {
+				t.Fatal(err)
+			}
+			if len(test.wantErr) > 0 && (err == nil || err.Error() != test.wantErr) {
+				t.Fatalf("getConfig() error = %v, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
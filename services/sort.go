@@ -0,0 +1,181 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"tempo-worklog/constants"
+	"tempo-worklog/models"
+	"unicode"
+)
+
+type SortService struct {
+	issuesOrder string
+	usersOrder  string
+}
+
+func NewSortService(issuesOrder, usersOrder string) *SortService {
+	return &SortService{issuesOrder: issuesOrder, usersOrder: usersOrder}
+}
+
+// Sort orders users and issues of the worklog, so the report rows are the same on every run.
+func (s *SortService) Sort(worklog *models.Worklog) error {
+	for _, project := range worklog.Projects {
+		err := s.sortUsers(project.Users)
+		if err != nil {
+			return err
+		}
+
+		for _, user := range project.Users {
+			err = s.sortIssues(user.Issues)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *SortService) sortUsers(users []models.User) error {
+	var compare func(a, b models.User) int
+
+	switch s.usersOrder {
+	case constants.UsersOrderName:
+		compare = func(a, b models.User) int {
+			return 0
+		}
+	case constants.UsersOrderPosition:
+		compare = func(a, b models.User) int {
+			return strings.Compare(strings.ToLower(a.Position), strings.ToLower(b.Position))
+		}
+	case constants.UsersOrderCost:
+		compare = func(a, b models.User) int {
+			return s.getUserCost(b) - s.getUserCost(a) // descending
+		}
+	default:
+		return errors.New("unknown users order: " + s.usersOrder)
+	}
+
+	sort.SliceStable(users, func(i, j int) bool {
+		if result := compare(users[i], users[j]); result != 0 {
+			return result < 0
+		}
+
+		// users with the same value are ordered by name
+		nameA, nameB := strings.ToLower(users[i].DisplayName), strings.ToLower(users[j].DisplayName)
+		if nameA != nameB {
+			return nameA < nameB
+		}
+		return users[i].AccountId < users[j].AccountId
+	})
+
+	return nil
+}
+
+func (s *SortService) sortIssues(issues []models.Issue) error {
+	var compare func(a, b models.Issue) int
+
+	switch s.issuesOrder {
+	case constants.IssuesOrderKey:
+		compare = func(a, b models.Issue) int {
+			return 0
+		}
+	case constants.IssuesOrderHours:
+		compare = func(a, b models.Issue) int {
+			return s.getIssueSeconds(b) - s.getIssueSeconds(a) // descending
+		}
+	case constants.IssuesOrderFirstLogged:
+		compare = func(a, b models.Issue) int {
+			return strings.Compare(s.getIssueFirstDate(a), s.getIssueFirstDate(b))
+		}
+	case constants.IssuesOrderSummary:
+		compare = func(a, b models.Issue) int {
+			return strings.Compare(strings.ToLower(a.Summary), strings.ToLower(b.Summary))
+		}
+	default:
+		return errors.New("unknown issues order: " + s.issuesOrder)
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if result := compare(issues[i], issues[j]); result != 0 {
+			return result < 0
+		}
+
+		// issues with the same value are ordered by key, parts of the same issue by attribute and work type
+		if issues[i].Key != issues[j].Key {
+			return s.lessIssueKey(issues[i].Key, issues[j].Key)
+		}
+		if issues[i].Attribute != issues[j].Attribute {
+			return issues[i].Attribute < issues[j].Attribute
+		}
+		return issues[i].WorkType < issues[j].WorkType
+	})
+
+	return nil
+}
+
+// lessIssueKey compares issue keys in natural order, so PRJ-2 goes before PRJ-10.
+func (s *SortService) lessIssueKey(a, b string) bool {
+	prefixA, numberA := s.splitIssueKey(a)
+	prefixB, numberB := s.splitIssueKey(b)
+
+	if prefixA != prefixB {
+		return prefixA < prefixB
+	}
+	if numberA != numberB {
+		return numberA < numberB
+	}
+	return a < b
+}
+
+func (s *SortService) splitIssueKey(key string) (string, int) {
+	digits := strings.TrimLeftFunc(key[strings.LastIndex(key, "-")+1:], func(r rune) bool {
+		return !unicode.IsDigit(r)
+	})
+
+	number, err := strconv.Atoi(digits)
+	if err != nil {
+		return key, 0
+	}
+
+	return strings.TrimSuffix(key, digits), number
+}
+
+// getUserCost is cost of user in rate by seconds, rate overrides of work types are taken into account.
+func (s *SortService) getUserCost(user models.User) int {
+	cost := 0
+	for _, issue := range user.Issues {
+		rate := user.Rate
+		if issue.Rate > 0 {
+			rate = issue.Rate
+		}
+		if issue.Unapproved {
+			rate = 0
+		}
+		cost += s.getIssueSeconds(issue) * rate
+	}
+
+	return cost
+}
+
+func (s *SortService) getIssueSeconds(issue models.Issue) int {
+	seconds := 0
+	for _, effort := range issue.Efforts {
+		seconds += effort.TimeSpentSeconds
+	}
+
+	return seconds
+}
+
+func (s *SortService) getIssueFirstDate(issue models.Issue) string {
+	firstDate := ""
+	for _, effort := range issue.Efforts {
+		if firstDate == "" || effort.Date < firstDate {
+			firstDate = effort.Date
+		}
+	}
+
+	return firstDate
+}
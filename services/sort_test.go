@@ -0,0 +1,112 @@
+package services
+
+import (
+	"reflect"
+	"tempo-worklog/models"
+	"testing"
+)
+
+func TestSortIssues(t *testing.T) {
+	issues := []models.Issue{
+		{Key: "PRJ-10", Summary: "b", Efforts: []models.Effort{{Date: "2023-01-03", TimeSpentSeconds: 3600}}},
+		{Key: "PRJ-2", Summary: "C", Efforts: []models.Effort{{Date: "2023-01-04", TimeSpentSeconds: 7200}}},
+		{Key: "ABC-1", Summary: "a", Efforts: []models.Effort{{Date: "2023-01-05", TimeSpentSeconds: 3600}}},
+		{Key: "PRJ-1", Summary: "b", Efforts: []models.Effort{
+			{Date: "2023-01-06", TimeSpentSeconds: 1800},
+			{Date: "2023-01-02", TimeSpentSeconds: 1800},
+		}},
+	}
+
+	tests := []struct {
+		order string
+		want  []string
+	}{
+		{"key", []string{"ABC-1", "PRJ-1", "PRJ-2", "PRJ-10"}},
+		{"hours", []string{"PRJ-2", "ABC-1", "PRJ-1", "PRJ-10"}},
+		{"first_logged", []string{"PRJ-1", "PRJ-10", "PRJ-2", "ABC-1"}},
+		{"summary", []string{"ABC-1", "PRJ-1", "PRJ-10", "PRJ-2"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.order, func(t *testing.T) {
+			sorted := append([]models.Issue{}, issues...)
+
+			err := NewSortService(test.order, "name").sortIssues(sorted)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var keys []string
+			for _, issue := range sorted {
+				keys = append(keys, issue.Key)
+			}
+
+			if !reflect.DeepEqual(keys, test.want) {
+				t.Errorf("sortIssues() = %v, want %v", keys, test.want)
+			}
+		})
+	}
+}
+
+func TestSortUsers(t *testing.T) {
+	issues := func(seconds int) []models.Issue {
+		return []models.Issue{{Efforts: []models.Effort{{TimeSpentSeconds: seconds}}}}
+	}
+
+	users := []models.User{
+		{AccountId: "3", DisplayName: "bob", Position: "QA", Rate: 10, Issues: issues(3600)},
+		{AccountId: "2", DisplayName: "Alice", Position: "Developer", Rate: 20, Issues: issues(3600)},
+		{AccountId: "1", DisplayName: "Bob", Position: "Developer", Rate: 5, Issues: issues(7200)},
+	}
+
+	tests := []struct {
+		order string
+		want  []string
+	}{
+		{"name", []string{"2", "1", "3"}},
+		{"position", []string{"2", "1", "3"}},
+		{"cost", []string{"2", "1", "3"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.order, func(t *testing.T) {
+			sorted := append([]models.User{}, users...)
+
+			err := NewSortService("key", test.order).sortUsers(sorted)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var accountIds []string
+			for _, user := range sorted {
+				accountIds = append(accountIds, user.AccountId)
+			}
+
+			if !reflect.DeepEqual(accountIds, test.want) {
+				t.Errorf("sortUsers() = %v, want %v", accountIds, test.want)
+			}
+		})
+	}
+}
+
+func TestSortUnknownOrder(t *testing.T) {
+	tests := []struct {
+		issuesOrder string
+		usersOrder  string
+		wantErr     string
+	}{
+		{"priority", "name", "unknown issues order: priority"},
+		{"key", "team", "unknown users order: team"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.wantErr, func(t *testing.T) {
+			worklog := newTestWorklog("PRJ")
+
+			err := NewSortService(test.issuesOrder, test.usersOrder).Sort(worklog)
+			if err == nil || err.Error() != test.wantErr {
+				t.Fatalf("Sort() error = %v, want %q", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -3,26 +3,48 @@ package services
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
+	neturl "net/url"
 	"sort"
+	"strconv"
 	"strings"
+	"tempo-worklog/constants"
 	"tempo-worklog/models"
 	"time"
 )
 
 type WorklogService struct {
-	jiraUser                   string
-	jiraToken                  string
-	tempoToken                 string
-	tempoWorklogUrlTemplate    string
-	jiraSearchIssueUrlTemplate string
-	projectConfigService       *ProjectConfigService
+	jiraUser                    string
+	jiraToken                   string
+	tempoToken                  string
+	tempoWorklogUrlTemplate     string
+	tempoUserWorklogUrlTemplate string
+	tempoTeamsUrl               string
+	tempoTeamMembersUrl         string
+	tempoApprovalUrlTemplate    string
+	tempoPlanUrlTemplate        string
+	tempoUserPlanUrlTemplate    string
+	jiraSearchIssueUrlTemplate  string
+	jiraProjectUrlTemplate      string
+	jiraUserUrlTemplate         string
+	tempoTeams                  bool
+	costBasis                   string
+	issueFields                 []string
+	groupBy                     string
+	attributesConfig            models.AttributesAppConfig
+	projectConfigs              map[string]models.ProjectAppConfig
+	projectConfigService        *ProjectConfigService
+	jiraProjects                map[string]models.JiraProject // cache of Jira projects by key
 }
 
-func NewWorklogService(jiraUrl, jiraUser, jiraToken, tempoToken string, projectConfigService *ProjectConfigService) *WorklogService {
+func NewWorklogService(jiraUrl, jiraUser, jiraToken, tempoToken string, tempoTeams bool, costBasis string, issueFields []string,
+	groupBy string, attributesConfig models.AttributesAppConfig, projectConfigs map[string]models.ProjectAppConfig,
+	projectConfigService *ProjectConfigService) *WorklogService {
+	fields := append([]string{"summary", "issuetype", "parent"}, issueFields...)
 
 	return &WorklogService{
 		jiraUser:   jiraUser,
@@ -32,25 +54,70 @@ func NewWorklogService(jiraUrl, jiraUser, jiraToken, tempoToken string, projectC
 		// https://api.tempo.io/core/3/worklogs?projectId=PRJ&limit=10&from=2019-12-27&to=2020-07-20
 		tempoWorklogUrlTemplate: "https://api.tempo.io/core/3/worklogs?project=%s&from=%s&to=%s&offset=%d&limit=%d",
 
+		// https://api.tempo.io/core/3/worklogs/user/123?limit=10&from=2019-12-27&to=2020-07-20
+		tempoUserWorklogUrlTemplate: "https://api.tempo.io/core/3/worklogs/user/%s?from=%s&to=%s&offset=%d&limit=%d",
+
+		// https://api.tempo.io/core/3/teams
+		tempoTeamsUrl: "https://api.tempo.io/core/3/teams",
+
+		// https://api.tempo.io/core/3/teams/1/members
+		tempoTeamMembersUrl: "https://api.tempo.io/core/3/teams/%d/members",
+
+		// https://api.tempo.io/core/3/timesheet-approvals/user/123?from=2020-07-01&to=2020-07-31
+		tempoApprovalUrlTemplate: "https://api.tempo.io/core/3/timesheet-approvals/user/%s?from=%s&to=%s",
+
+		// https://api.tempo.io/core/3/plans?from=2020-07-01&to=2020-07-31&offset=0&limit=100
+		tempoPlanUrlTemplate: "https://api.tempo.io/core/3/plans?from=%s&to=%s&offset=%d&limit=%d",
+
+		// https://api.tempo.io/core/3/plans/user/123?from=2020-07-01&to=2020-07-31&offset=0&limit=100
+		tempoUserPlanUrlTemplate: "https://api.tempo.io/core/3/plans/user/%s?from=%s&to=%s&offset=%d&limit=%d",
+
 		// https://company.atlassian.net/rest/api/2/search?fields=summary&jql=key%20in%20(PRJ-384,PRJ-502)&startAt=1&maxResults=1
-		jiraSearchIssueUrlTemplate: strings.TrimRight(jiraUrl, "/") + "/rest/api/2/search?fields=summary&jql=key%%20in%%20(%s)&startAt=%d&maxResults=%d",
+		jiraSearchIssueUrlTemplate: strings.TrimRight(jiraUrl, "/") + "/rest/api/2/search?fields=" + strings.Join(fields, ",") + "&jql=%s&startAt=%d&maxResults=%d",
+
+		// https://company.atlassian.net/rest/api/2/project/10000
+		jiraProjectUrlTemplate: strings.TrimRight(jiraUrl, "/") + "/rest/api/2/project/%s",
 
+		// https://company.atlassian.net/rest/api/2/user?accountId=123
+		jiraUserUrlTemplate: strings.TrimRight(jiraUrl, "/") + "/rest/api/2/user?accountId=%s",
+
+		tempoTeams:           tempoTeams,
+		costBasis:            costBasis,
+		issueFields:          issueFields,
+		groupBy:              groupBy,
+		attributesConfig:     attributesConfig,
+		projectConfigs:       projectConfigs,
 		projectConfigService: projectConfigService,
+		jiraProjects:         map[string]models.JiraProject{},
 	}
 }
 
-func (s *WorklogService) GetWorklog(projectKeys []string, dateFrom, dateTo string) (*models.Worklog, error) {
+// GetWorklog fetches worklog of the projects, worklog is restricted to issues matching the JQL filter if it is set.
+func (s *WorklogService) GetWorklog(projectKeys []string, dateFrom, dateTo, jql string) (*models.Worklog, error) {
 	projectConfigWrapper, err := s.projectConfigService.Get()
 	if err != nil {
 		return nil, err
 	}
 
+	accountIdToTeam := map[string]string{}
+	if s.tempoTeams {
+		accountIdToTeam, err = s.getAccountIdToTeam()
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var projects []models.Project
 
 	log.Println("Getting tempo report started")
 
 	for _, projectKey := range projectKeys {
-		project, err := s.getProject(projectKey, dateFrom, dateTo, projectConfigWrapper)
+		tempoResults, err := s.getTempoResults(projectKey+" project", s.tempoWorklogUrlTemplate, projectKey, dateFrom, dateTo)
+		if err != nil {
+			return nil, err
+		}
+
+		project, err := s.getProject(projectKey, jql, tempoResults, projectConfigWrapper, accountIdToTeam)
 		if err != nil {
 			return nil, err
 		}
@@ -59,7 +126,7 @@ func (s *WorklogService) GetWorklog(projectKeys []string, dateFrom, dateTo strin
 
 	log.Println("Getting tempo report finished")
 
-	worklog := &models.Worklog{Projects: projects}
+	worklog := &models.Worklog{By: constants.ReportByProject, Projects: projects}
 	//fmt.Println("worklog", worklog)
 
 	err = s.projectConfigService.Save(projectConfigWrapper, worklog)
@@ -70,20 +137,431 @@ func (s *WorklogService) GetWorklog(projectKeys []string, dateFrom, dateTo strin
 	return worklog, nil
 }
 
-func (s *WorklogService) getProject(projectKey, dateFrom, dateTo string, projectConfigWrapper *models.ProjectConfigWrapper) (*models.Project, error) {
-	// get tempo worklog
+// GetUserWorklog fetches worklog of the users in all projects, users are given by account ids or Tempo teams with "team:" prefix.
+func (s *WorklogService) GetUserWorklog(userKeys []string, dateFrom, dateTo, jql string) (*models.Worklog, error) {
+	accountIds, err := s.getAccountIds(userKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	projectConfigWrapper, err := s.projectConfigService.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	accountIdToTeam := map[string]string{}
+	if s.tempoTeams {
+		accountIdToTeam, err = s.getAccountIdToTeam()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	log.Println("Getting tempo report started")
+
+	projectKeyToResults := map[string][]models.TempoResult{} // group by project of issue
+
+	for _, accountId := range accountIds {
+		tempoResults, err := s.getTempoResults(accountId+" user", s.tempoUserWorklogUrlTemplate, accountId, dateFrom, dateTo)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, result := range tempoResults {
+			projectKey := s.getProjectKey(result.Issue.Key)
+			projectKeyToResults[projectKey] = append(projectKeyToResults[projectKey], result)
+		}
+	}
+
+	projectKeys := make([]string, 0, len(projectKeyToResults))
+	for projectKey := range projectKeyToResults {
+		projectKeys = append(projectKeys, projectKey)
+	}
+	sort.Strings(projectKeys)
+
+	var projects []models.Project
+
+	for _, projectKey := range projectKeys {
+		project, err := s.getProject(projectKey, jql, projectKeyToResults[projectKey], projectConfigWrapper, accountIdToTeam)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, *project)
+	}
+
+	log.Println("Getting tempo report finished")
+
+	worklog := &models.Worklog{By: constants.ReportByUser, Projects: projects}
+
+	err = s.projectConfigService.Save(projectConfigWrapper, worklog)
+	if err != nil {
+		return nil, err
+	}
+
+	return worklog, nil
+}
+
+// GetApprovals fetches timesheet approvals of users of the worklog for every approval period within the dates.
+func (s *WorklogService) GetApprovals(worklog *models.Worklog, dateFrom, dateTo string) (map[string][]models.TempoApproval, error) {
+	accountIdToApprovals := map[string][]models.TempoApproval{}
+
+	for _, project := range worklog.Projects {
+		for _, user := range project.Users {
+			if _, ok := accountIdToApprovals[user.AccountId]; ok {
+				continue
+			}
+
+			approvals, err := s.getUserApprovals(user.AccountId, dateFrom, dateTo)
+			if err != nil {
+				return nil, err
+			}
+
+			accountIdToApprovals[user.AccountId] = approvals
+		}
+	}
+
+	log.Println("Fetched tempo timesheet approvals:", len(accountIdToApprovals), "users")
+
+	return accountIdToApprovals, nil
+}
+
+// getUserApprovals walks approval periods of the user, Tempo returns the period the from date falls into.
+func (s *WorklogService) getUserApprovals(accountId, dateFrom, dateTo string) ([]models.TempoApproval, error) {
+	var approvals []models.TempoApproval
+
+	from := dateFrom
+	for from <= dateTo {
+		approval := models.TempoApproval{}
+		err := s.getTempoResponse(fmt.Sprintf(s.tempoApprovalUrlTemplate, accountId, from, dateTo), &approval)
+		if err != nil {
+			return nil, err
+		}
+
+		if approval.Period.To == "" || approval.Period.To < from {
+			return nil, errors.New("no timesheet approval period of user " + accountId + " for " + from)
+		}
+
+		approvals = append(approvals, approval)
+
+		periodTo, err := time.Parse(constants.InputDateFormat, approval.Period.To)
+		if err != nil {
+			return nil, err
+		}
+
+		from = periodTo.AddDate(0, 0, 1).Format(constants.InputDateFormat)
+	}
+
+	return approvals, nil
+}
+
+// GetPlans fetches Tempo plans within the dates and puts them to projects of the worklog, plans of other projects
+// are skipped before their issues are resolved. Plans of all users are fetched for project worklog and plans of the users
+// (Tempo teams are expanded) and users of the worklog are fetched for user worklog, so users planned without worklog are included too.
+func (s *WorklogService) GetPlans(worklog *models.Worklog, userKeys []string, dateFrom, dateTo string) error {
+	accountIdToName := map[string]string{}
+	projectKeyToPosition := map[string]int{}
+	var projectKeys []string
+
+	for i, project := range worklog.Projects {
+		projectKeyToPosition[project.Key] = i
+		projectKeys = append(projectKeys, project.Key)
+		worklog.Projects[i].Plans = nil
+
+		for _, user := range project.Users {
+			accountIdToName[user.AccountId] = user.DisplayName
+		}
+	}
+
+	if len(projectKeys) == 0 {
+		return nil
+	}
+
+	var plans []models.TempoPlan
+	var err error
+	if worklog.By == constants.ReportByUser {
+		plans, err = s.getUserPlans(worklog, userKeys, dateFrom, dateTo)
+	} else {
+		plans, err = s.getPlanPages(s.tempoPlanUrlTemplate, dateFrom, dateTo)
+	}
+	if err != nil {
+		return err
+	}
+
+	planItemToProjectKey, err := s.getPlanItemProjectKeys(plans, projectKeys)
+	if err != nil {
+		return err
+	}
+
+	var accountIds []string
+	accountIdToProjectSeconds := map[string]map[string]int{} // group plans by user and project of the worklog
+
+	for _, plan := range plans {
+		projectKey, ok := planItemToProjectKey[plan.PlanItem.Type+":"+plan.PlanItem.Id.String()]
+		if !ok {
+			continue
+		}
+
+		accountId := plan.Assignee.AccountId
+		if _, ok := accountIdToProjectSeconds[accountId]; !ok {
+			accountIdToProjectSeconds[accountId] = map[string]int{}
+			accountIds = append(accountIds, accountId)
+		}
+		accountIdToProjectSeconds[accountId][projectKey] += plan.TotalPlannedSecondsInScope
+	}
+
+	for _, accountId := range accountIds {
+		name, ok := accountIdToName[accountId]
+		if !ok {
+			name, err = s.getUserDisplayName(accountId)
+			if err != nil {
+				return err
+			}
+		}
+
+		for projectKey, seconds := range accountIdToProjectSeconds[accountId] {
+			project := &worklog.Projects[projectKeyToPosition[projectKey]]
+			project.Plans = append(project.Plans, models.Plan{AccountId: accountId, DisplayName: name, PlannedSeconds: seconds})
+		}
+	}
+
+	log.Println("Fetched tempo plans:", len(plans), "plans,", len(accountIds), "users")
+
+	return nil
+}
+
+// getPlanPages fetches all pages of Tempo plans, the URL template is completed by offset and limit,
+// plans of generic resources are skipped.
+func (s *WorklogService) getPlanPages(urlTemplate string, args ...interface{}) ([]models.TempoPlan, error) {
+	var plans []models.TempoPlan
+	offset := 0
+	limit := 100
+
+	for {
+		plansResponse := &models.TempoPlansResponse{}
+		err := s.getTempoResponse(fmt.Sprintf(urlTemplate, append(args, offset, limit)...), plansResponse)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, plan := range plansResponse.Results {
+			if plan.Assignee.Type == "USER" {
+				plans = append(plans, plan)
+			}
+		}
+
+		if plansResponse.Metadata.Count < plansResponse.Metadata.Limit {
+			break
+		}
+		offset = plansResponse.Metadata.Count + plansResponse.Metadata.Offset
+	}
+
+	return plans, nil
+}
+
+// getUserPlans fetches Tempo plans of the users and of users of the worklog within the dates.
+func (s *WorklogService) getUserPlans(worklog *models.Worklog, userKeys []string, dateFrom, dateTo string) ([]models.TempoPlan, error) {
+	accountIds, err := s.getAccountIds(userKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	accountIdToExists := map[string]bool{}
+	for _, accountId := range accountIds {
+		accountIdToExists[accountId] = true
+	}
+
+	for _, project := range worklog.Projects {
+		for _, user := range project.Users {
+			if !accountIdToExists[user.AccountId] {
+				accountIdToExists[user.AccountId] = true
+				accountIds = append(accountIds, user.AccountId)
+			}
+		}
+	}
+
+	var plans []models.TempoPlan
+
+	for _, accountId := range accountIds {
+		userPlans, err := s.getPlanPages(s.tempoUserPlanUrlTemplate, accountId, dateFrom, dateTo)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, userPlans...)
+
+		log.Println("Fetched tempo plans for", accountId, "user:", len(userPlans), "plans")
+	}
+
+	return plans, nil
+}
+
+// getUserDisplayName fetches name of Jira user, it is needed for users planned without worklog.
+func (s *WorklogService) getUserDisplayName(accountId string) (string, error) {
+	user := &models.JiraUser{}
+	err := s.getJiraResponse(fmt.Sprintf(s.jiraUserUrlTemplate, neturl.QueryEscape(accountId)), user)
+	if err != nil {
+		return "", err
+	}
+
+	if len(user.DisplayName) == 0 {
+		return accountId, nil
+	}
+
+	return user.DisplayName, nil
+}
+
+// getPlanItemProjectKeys maps plan items (type and id) of the projects to the project keys, items of other projects
+// are not mapped. Project items are matched by ids of the projects and issues are searched within the projects only.
+func (s *WorklogService) getPlanItemProjectKeys(plans []models.TempoPlan, projectKeys []string) (map[string]string, error) {
+	planItemToProjectKey := map[string]string{}
+
+	projectIdToKey := map[string]string{}
+	for _, projectKey := range projectKeys {
+		project, err := s.getJiraProject(projectKey)
+		if err != nil {
+			return nil, err
+		}
+		projectIdToKey[project.Id] = projectKey
+	}
+
+	var issueIds []string
+	issueIdToExists := map[string]bool{}
+
+	for _, plan := range plans {
+		id := plan.PlanItem.Id.String()
+
+		switch plan.PlanItem.Type {
+		case "PROJECT":
+			if projectKey, ok := projectIdToKey[id]; ok {
+				planItemToProjectKey["PROJECT:"+id] = projectKey
+			}
+		case "ISSUE":
+			if !issueIdToExists[id] {
+				issueIdToExists[id] = true
+				issueIds = append(issueIds, id)
+			}
+		}
+	}
+
+	chunkSize := 100
+
+	for start := 0; start < len(issueIds); start += chunkSize {
+		end := start + chunkSize
+		if end > len(issueIds) {
+			end = len(issueIds)
+		}
+
+		jql := "project in (" + strings.Join(projectKeys, ",") + ") AND id in (" + strings.Join(issueIds[start:end], ",") + ")"
+		issueIdToKey, err := s.searchIssueIds(jql)
+		if err != nil {
+			return nil, err
+		}
+
+		for issueId, issueKey := range issueIdToKey {
+			planItemToProjectKey["ISSUE:"+issueId] = s.getProjectKey(issueKey)
+		}
+	}
+
+	return planItemToProjectKey, nil
+}
+
+// getJiraProject fetches Jira project by key, projects are cached since plans of every report need them.
+func (s *WorklogService) getJiraProject(projectKey string) (*models.JiraProject, error) {
+	if project, ok := s.jiraProjects[projectKey]; ok {
+		return &project, nil
+	}
+
+	project := &models.JiraProject{}
+	err := s.getJiraResponse(fmt.Sprintf(s.jiraProjectUrlTemplate, neturl.PathEscape(projectKey)), project)
+	if err != nil {
+		return nil, err
+	}
+	s.jiraProjects[projectKey] = *project
+
+	return project, nil
+}
+
+// getAccountIds expands Tempo teams to account ids of their members, duplicates are skipped.
+func (s *WorklogService) getAccountIds(userKeys []string) ([]string, error) {
+	var accountIds []string
+	accountIdToExists := map[string]bool{}
+
+	for _, userKey := range userKeys {
+		keyAccountIds := []string{userKey}
+
+		if strings.HasPrefix(userKey, constants.TeamPrefix) {
+			var err error
+			keyAccountIds, err = s.getTeamAccountIds(strings.TrimPrefix(userKey, constants.TeamPrefix))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for _, accountId := range keyAccountIds {
+			if !accountIdToExists[accountId] {
+				accountIdToExists[accountId] = true
+				accountIds = append(accountIds, accountId)
+			}
+		}
+	}
+
+	return accountIds, nil
+}
+
+func (s *WorklogService) getTeamAccountIds(teamName string) ([]string, error) {
+	teamsResponse := &models.TempoTeamsResponse{}
+	err := s.getTempoResponse(s.tempoTeamsUrl, teamsResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, team := range teamsResponse.Results {
+		if !strings.EqualFold(team.Name, teamName) {
+			continue
+		}
+
+		membersResponse := &models.TempoTeamMembersResponse{}
+		err = s.getTempoResponse(fmt.Sprintf(s.tempoTeamMembersUrl, team.Id), membersResponse)
+		if err != nil {
+			return nil, err
+		}
+
+		var accountIds []string
+		for _, member := range membersResponse.Results {
+			accountIds = append(accountIds, member.Member.AccountId)
+		}
+
+		log.Println("Fetched tempo team", team.Name+":", len(accountIds), "members")
+
+		return accountIds, nil
+	}
+
+	return nil, errors.New("unknown tempo team: " + teamName)
+}
+
+// getProjectKey takes project key from issue key, e.g. PRJ from PRJ-123.
+func (s *WorklogService) getProjectKey(issueKey string) string {
+	index := strings.LastIndex(issueKey, "-")
+	if index < 0 {
+		return issueKey
+	}
+
+	return issueKey[:index]
+}
+
+// getTempoResults fetches all pages of tempo worklog, offset and limit are appended to the url arguments.
+func (s *WorklogService) getTempoResults(name, urlTemplate string, args ...interface{}) ([]models.TempoResult, error) {
 	var tempoResults []models.TempoResult
 	offset := 0
 	limit := 100
 
 	for {
-		response, err := s.getTempoWorklog(projectKey, dateFrom, dateTo, offset, limit)
+		response, err := s.getTempoWorklog(fmt.Sprintf(urlTemplate, append(args, offset, limit)...))
 		if err != nil {
 			return nil, err
 		}
 
 		//fmt.Println("Tempo response:", response)
-		log.Println("Fetched tempo report for", projectKey, "project:", response.Metadata.Count, "records")
+		log.Println("Fetched tempo report for", name+":", response.Metadata.Count, "records")
 
 		tempoResults = append(tempoResults, response.Results...)
 
@@ -94,9 +572,26 @@ func (s *WorklogService) getProject(projectKey, dateFrom, dateTo string, project
 	}
 	//fmt.Println("tempoResults", tempoResults)
 
+	return tempoResults, nil
+}
+
+func (s *WorklogService) getProject(projectKey, jql string, tempoResults []models.TempoResult,
+	projectConfigWrapper *models.ProjectConfigWrapper, accountIdToTeam map[string]string) (*models.Project, error) {
+	tempoResults, err := s.filterTempoResults(projectKey, jql, tempoResults)
+	if err != nil {
+		return nil, err
+	}
+
+	tempoResults = s.filterTempoResultsByAttributes(projectKey, tempoResults)
+
+	issueKeyToFields, parentKeyToFields, err := s.getResultsIssueFields(tempoResults)
+	if err != nil {
+		return nil, err
+	}
+
 	// convert tempo worklog to internal structure
 	projectConfig := projectConfigWrapper.ProjectKeyToConfig[projectKey]
-	users, err := s.getUsers(tempoResults, &projectConfig)
+	users, err := s.getUsers(tempoResults, &projectConfig, accountIdToTeam, issueKeyToFields, parentKeyToFields)
 	if err != nil {
 		return nil, err
 	}
@@ -104,14 +599,122 @@ func (s *WorklogService) getProject(projectKey, dateFrom, dateTo string, project
 	return &models.Project{Key: projectKey, Users: users}, nil
 }
 
-func (s *WorklogService) getTempoWorklog(projectKey, dateFrom, dateTo string, offset, limit int) (*models.TempoResponse, error) {
-	url := fmt.Sprintf(s.tempoWorklogUrlTemplate, projectKey, dateFrom, dateTo, offset, limit)
+// filterTempoResults keeps results of issues matching both JQL filter of input args and JQL filter of project config.
+func (s *WorklogService) filterTempoResults(projectKey, jql string, results []models.TempoResult) ([]models.TempoResult, error) {
+	var filters []string
+	for _, filter := range []string{jql, s.projectConfigs[strings.ToLower(projectKey)].Jql} {
+		if len(strings.TrimSpace(filter)) > 0 {
+			filters = append(filters, "("+filter+")")
+		}
+	}
+
+	if len(filters) == 0 {
+		return results, nil
+	}
+
+	filter := "project = \"" + projectKey + "\" AND " + strings.Join(filters, " AND ")
+
+	issueKeyToFields, err := s.searchIssues(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var filteredResults []models.TempoResult
+	for _, result := range results {
+		if _, ok := issueKeyToFields[result.Issue.Key]; ok {
+			filteredResults = append(filteredResults, result)
+		}
+	}
+
+	log.Println("Filtered tempo report for", projectKey, "project by JQL:", len(filteredResults), "of", len(results), "records")
+
+	return filteredResults, nil
+}
+
+// filterTempoResultsByAttributes keeps results with work attribute values allowed by the attributes filter.
+func (s *WorklogService) filterTempoResultsByAttributes(projectKey string, results []models.TempoResult) []models.TempoResult {
+	if len(s.attributesConfig.Filter) == 0 {
+		return results
+	}
+
+	var filteredResults []models.TempoResult
+
+	for _, result := range results {
+		allowed := true
+		for key, values := range s.attributesConfig.Filter {
+			if !s.containsValue(values, s.getAttribute(result, key)) {
+				allowed = false
+				break
+			}
+		}
+
+		if allowed {
+			filteredResults = append(filteredResults, result)
+		}
+	}
+
+	log.Println("Filtered tempo report for", projectKey, "project by attributes:", len(filteredResults), "of", len(results), "records")
+
+	return filteredResults
+}
+
+// getAttribute returns value of work attribute, keys are compared case-insensitively as config keys are lower case.
+func (s *WorklogService) getAttribute(result models.TempoResult, key string) string {
+	if len(key) == 0 {
+		return ""
+	}
+
+	for _, attribute := range result.Attributes.Values {
+		if strings.EqualFold(attribute.Key, key) {
+			return attribute.Value
+		}
+	}
+
+	return ""
+}
+
+// getWorkTypeRate returns work type of the result and its rate if the rate is overridden.
+func (s *WorklogService) getWorkTypeRate(result models.TempoResult) (string, int) {
+	workType := s.getAttribute(result, s.attributesConfig.WorkTypeKey)
+	if len(workType) == 0 {
+		return "", 0
+	}
+
+	for rateWorkType, rate := range s.attributesConfig.WorkTypeRates {
+		if strings.EqualFold(rateWorkType, workType) {
+			return workType, rate
+		}
+	}
+
+	return "", 0
+}
+
+func (s *WorklogService) containsValue(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *WorklogService) getTempoWorklog(url string) (*models.TempoResponse, error) {
+	tempoResponse := &models.TempoResponse{}
+	err := s.getTempoResponse(url, tempoResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	return tempoResponse, nil
+}
 
+func (s *WorklogService) getTempoResponse(url string, tempoResponse interface{}) error {
 	client := http.Client{Timeout: time.Second * 60}
 
 	request, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	request.Header.Set("Authorization", "Bearer "+s.tempoToken)
@@ -119,7 +722,7 @@ func (s *WorklogService) getTempoWorklog(projectKey, dateFrom, dateTo string, of
 
 	response, err := client.Do(request)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if response.Body != nil {
@@ -128,19 +731,50 @@ func (s *WorklogService) getTempoWorklog(projectKey, dateFrom, dateTo string, of
 
 	body, err := ioutil.ReadAll(response.Body)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	tempoResponse := &models.TempoResponse{}
-	err = json.Unmarshal(body, tempoResponse)
+	return json.Unmarshal(body, tempoResponse)
+}
+
+// getAccountIdToTeam maps users to Tempo teams, the first team by name is taken for members of several teams.
+func (s *WorklogService) getAccountIdToTeam() (map[string]string, error) {
+	teamsResponse := &models.TempoTeamsResponse{}
+	err := s.getTempoResponse(s.tempoTeamsUrl, teamsResponse)
 	if err != nil {
 		return nil, err
 	}
 
-	return tempoResponse, nil
+	teams := teamsResponse.Results
+	sort.Slice(teams, func(i, j int) bool {
+		return strings.ToLower(teams[i].Name) < strings.ToLower(teams[j].Name)
+	})
+
+	accountIdToTeam := map[string]string{}
+
+	for _, team := range teams {
+		membersResponse := &models.TempoTeamMembersResponse{}
+		err = s.getTempoResponse(fmt.Sprintf(s.tempoTeamMembersUrl, team.Id), membersResponse)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, member := range membersResponse.Results {
+			if _, ok := accountIdToTeam[member.Member.AccountId]; !ok {
+				accountIdToTeam[member.Member.AccountId] = team.Name
+			}
+		}
+	}
+
+	log.Println("Fetched tempo teams:", len(teams), "teams,", len(accountIdToTeam), "members")
+
+	return accountIdToTeam, nil
 }
 
-func (s *WorklogService) getUsers(results []models.TempoResult, projectConfig *models.ProjectConfig) ([]models.User, error) {
+// getUsers converts tempo results to users with their issues and efforts, issues are described by the fields
+// fetched from Jira or read from import file.
+func (s *WorklogService) getUsers(results []models.TempoResult, projectConfig *models.ProjectConfig, accountIdToTeam map[string]string,
+	issueKeyToFields, parentKeyToFields map[string]models.JiraSearchIssueFields) ([]models.User, error) {
 	userIdToTempoResult := map[string][]models.TempoResult{} // group tempo results by account id
 
 	for _, result := range results {
@@ -156,7 +790,7 @@ func (s *WorklogService) getUsers(results []models.TempoResult, projectConfig *m
 	var users []models.User
 
 	for _, userResults := range userIdToTempoResult {
-		issues, err := s.getIssues(userResults)
+		issues, err := s.getIssues(userResults, issueKeyToFields, parentKeyToFields)
 		if err != nil {
 			return nil, err
 		}
@@ -164,11 +798,18 @@ func (s *WorklogService) getUsers(results []models.TempoResult, projectConfig *m
 		author := userResults[0].Author
 		userConfig := projectConfig.UserNameToConfig[author.DisplayName]
 
+		// team of project config takes precedence over tempo team
+		team := userConfig.Team
+		if len(team) == 0 {
+			team = accountIdToTeam[author.AccountId]
+		}
+
 		user := models.User{
 			AccountId:   author.AccountId,
 			DisplayName: author.DisplayName,
 			Position:    userConfig.Position,
 			Rate:        userConfig.Rate,
+			Team:        team,
 			Issues:      issues,
 		}
 		users = append(users, user)
@@ -182,7 +823,43 @@ func (s *WorklogService) getUsers(results []models.TempoResult, projectConfig *m
 	return users, nil
 }
 
-func (s *WorklogService) getIssues(results []models.TempoResult) ([]models.Issue, error) {
+// getResultsIssueFields fetches Jira fields of issues of the results and of parents of sub-tasks.
+func (s *WorklogService) getResultsIssueFields(results []models.TempoResult) (map[string]models.JiraSearchIssueFields,
+	map[string]models.JiraSearchIssueFields, error) {
+	issueKeyToExists := map[string]bool{}
+	var issueKeys []string
+	for _, result := range results {
+		if !issueKeyToExists[result.Issue.Key] {
+			issueKeyToExists[result.Issue.Key] = true
+			issueKeys = append(issueKeys, result.Issue.Key)
+		}
+	}
+
+	issueKeyToFields, err := s.getIssueKeyToFields(issueKeys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// epics of sub-tasks are taken from their parents
+	parentKeyToExists := map[string]bool{}
+	var parentKeys []string
+	for _, fields := range issueKeyToFields {
+		if fields.IssueType.Subtask && fields.Parent != nil && !parentKeyToExists[fields.Parent.Key] {
+			parentKeyToExists[fields.Parent.Key] = true
+			parentKeys = append(parentKeys, fields.Parent.Key)
+		}
+	}
+
+	parentKeyToFields, err := s.getIssueKeyToFields(parentKeys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return issueKeyToFields, parentKeyToFields, nil
+}
+
+func (s *WorklogService) getIssues(results []models.TempoResult,
+	issueKeyToFields, parentKeyToFields map[string]models.JiraSearchIssueFields) ([]models.Issue, error) {
 	issueKeyToResults := map[string][]models.TempoResult{} // group by issue id
 
 	for _, result := range results {
@@ -195,57 +872,177 @@ func (s *WorklogService) getIssues(results []models.TempoResult) ([]models.Issue
 		}
 	}
 
-	issueKeyToSummary, err := s.getIssueKeyToSummary(issueKeyToResults)
-	if err != nil {
-		return nil, err
+	var issues []models.Issue
+
+	for _, issueResults := range issueKeyToResults {
+		// issue is split by grouping attribute and by work types with rate override
+		partToResults := map[string][]models.TempoResult{}
+		for _, result := range issueResults {
+			workType, _ := s.getWorkTypeRate(result)
+			part := s.getAttribute(result, s.groupBy) + "\x00" + workType
+			partToResults[part] = append(partToResults[part], result)
+		}
+
+		for _, results := range partToResults {
+			issueKey := results[0].Issue.Key
+
+			efforts, err := s.getEfforts(results)
+			if err != nil {
+				return nil, err
+			}
+
+			fields := issueKeyToFields[issueKey]
+			epic := s.getEpic(issueKey, fields, parentKeyToFields)
+			workType, rate := s.getWorkTypeRate(results[0])
+
+			issue := models.Issue{
+				Key:       issueKey,
+				Summary:   fields.Summary,
+				Type:      fields.IssueType.Name,
+				Fields:    map[string]string{},
+				Attribute: s.getAttribute(results[0], s.groupBy),
+				WorkType:  workType,
+				Rate:      rate,
+				Efforts:   efforts,
+			}
+			for _, field := range s.issueFields {
+				issue.Fields[field] = s.getFieldText(fields.Extra[field])
+			}
+			if epic != nil {
+				issue.EpicKey = epic.Key
+				issue.EpicSummary = epic.Fields.Summary
+			}
+
+			issues = append(issues, issue)
+		}
 	}
+	//fmt.Println("issues", issues)
 
-	var issues []models.Issue
+	return issues, nil
+}
+
+// getEpic returns the parent of issue or the parent of sub-task parent, nil is returned for issues without epic.
+// Epic is the epic of itself, so time logged on it is grouped together with its issues.
+func (s *WorklogService) getEpic(issueKey string, fields models.JiraSearchIssueFields,
+	parentKeyToFields map[string]models.JiraSearchIssueFields) *models.JiraParentIssue {
+	// epics are at level 1 above issues at 0 and sub-tasks at -1
+	if fields.IssueType.HierarchyLevel == 1 {
+		return &models.JiraParentIssue{
+			Key:    issueKey,
+			Fields: models.JiraParentIssueFields{Summary: fields.Summary, IssueType: fields.IssueType},
+		}
+	}
 
-	for _, results := range issueKeyToResults {
-		issueKey := results[0].Issue.Key
+	if fields.Parent == nil {
+		return nil
+	}
 
-		efforts, err := s.getEfforts(results)
+	if !fields.IssueType.Subtask {
+		return fields.Parent
+	}
+
+	return parentKeyToFields[fields.Parent.Key].Parent
+}
+
+// getFieldText converts Jira field value to text: objects are shown by name, lists are joined with comma.
+func (s *WorklogService) getFieldText(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case bool:
+		return strconv.FormatBool(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case []interface{}:
+		texts := make([]string, 0, len(value))
+		for _, item := range value {
+			text := s.getFieldText(item)
+			if len(text) > 0 {
+				texts = append(texts, text)
+			}
+		}
+		return strings.Join(texts, ", ")
+	case map[string]interface{}:
+		// status, priority, versions, components, users and select options
+		for _, key := range []string{"name", "value", "displayName", "key"} {
+			if text, ok := value[key]; ok {
+				return s.getFieldText(text)
+			}
+		}
+		return ""
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// getIssueKeyToFields searches issues by chunks of keys to keep the search url short.
+func (s *WorklogService) getIssueKeyToFields(issueKeys []string) (map[string]models.JiraSearchIssueFields, error) {
+	issueKeyToFields := map[string]models.JiraSearchIssueFields{}
+	chunkSize := 100
+
+	for start := 0; start < len(issueKeys); start += chunkSize {
+		end := start + chunkSize
+		if end > len(issueKeys) {
+			end = len(issueKeys)
+		}
+
+		chunkKeyToFields, err := s.searchIssues("key in (" + strings.Join(issueKeys[start:end], ",") + ")")
 		if err != nil {
 			return nil, err
 		}
 
-		issue := models.Issue{
-			Key:     issueKey,
-			Summary: issueKeyToSummary[issueKey],
-			Efforts: efforts,
+		for issueKey, fields := range chunkKeyToFields {
+			issueKeyToFields[issueKey] = fields
 		}
-
-		issues = append(issues, issue)
 	}
-	//fmt.Println("issues", issues)
 
-	return issues, nil
+	return issueKeyToFields, nil
 }
 
-func (s *WorklogService) getIssueKeyToSummary(issueKeyToResults map[string][]models.TempoResult) (map[string]string, error) {
-	separator := ""
-	keys := ""
-	for key := range issueKeyToResults {
-		if keys != "" {
-			separator = ","
+// searchIssues returns fields of all issues matching the JQL.
+func (s *WorklogService) searchIssues(jql string) (map[string]models.JiraSearchIssueFields, error) {
+	issueKeyToFields := map[string]models.JiraSearchIssueFields{}
+	offset := 0
+	limit := 100
+
+	for {
+		response, err := s.searchIssuePage(jql, offset, limit)
+		if err != nil {
+			return nil, err
+		}
+		//fmt.Println("searchIssuePage", response)
+
+		for _, issue := range response.Issues {
+			issueKeyToFields[issue.Key] = issue.Fields
 		}
-		keys += separator + key
+
+		count := response.StartAt + len(response.Issues)
+		if count >= response.Total {
+			break
+		}
+		offset = count
 	}
+	//fmt.Println("issueKeyToFields", issueKeyToFields)
 
-	issueKeyToSummary := map[string]string{}
+	return issueKeyToFields, nil
+}
+
+// searchIssueIds returns keys of all issues matching the JQL by issue ids.
+func (s *WorklogService) searchIssueIds(jql string) (map[string]string, error) {
+	issueIdToKey := map[string]string{}
 	offset := 0
 	limit := 100
 
 	for {
-		response, err := s.searchIssueByKeys(keys, offset, limit)
+		response, err := s.searchIssuePage(jql, offset, limit)
 		if err != nil {
 			return nil, err
 		}
-		//fmt.Println("searchIssueByKeys", response)
 
 		for _, issue := range response.Issues {
-			issueKeyToSummary[issue.Key] = issue.Fields.Summary
+			issueIdToKey[issue.Id] = issue.Key
 		}
 
 		count := response.StartAt + len(response.Issues)
@@ -254,66 +1051,103 @@ func (s *WorklogService) getIssueKeyToSummary(issueKeyToResults map[string][]mod
 		}
 		offset = count
 	}
-	//fmt.Println("issueKeyToSummary", issueKeyToSummary)
 
-	return issueKeyToSummary, nil
+	return issueIdToKey, nil
 }
 
-func (s *WorklogService) searchIssueByKeys(keys string, offset, limit int) (*models.JiraSearchIssueResponse, error) {
-	url := fmt.Sprintf(s.jiraSearchIssueUrlTemplate, keys, offset, limit)
+func (s *WorklogService) searchIssuePage(jql string, offset, limit int) (*models.JiraSearchIssueResponse, error) {
+	url := fmt.Sprintf(s.jiraSearchIssueUrlTemplate, neturl.QueryEscape(jql), offset, limit)
 
-	client := http.Client{Timeout: time.Second * 60}
+	body, err := s.getJiraBody(url)
+	if err != nil {
+		return nil, err
+	}
 
-	request, err := http.NewRequest(http.MethodGet, url, nil)
+	jiraSearchIssueResponse := &models.JiraSearchIssueResponse{}
+	err = json.Unmarshal(body, jiraSearchIssueResponse)
 	if err != nil {
 		return nil, err
 	}
 
-	encodedToken := base64.URLEncoding.EncodeToString([]byte(s.jiraUser + ":" + s.jiraToken))
-	request.Header.Set("Authorization", "Basic "+encodedToken)
-	request.Header.Set("Content-Type", "application/json")
+	if len(s.issueFields) == 0 {
+		return jiraSearchIssueResponse, nil
+	}
 
-	response, err := client.Do(request)
+	jiraSearchIssueRawResponse := &models.JiraSearchIssueRawResponse{}
+	err = json.Unmarshal(body, jiraSearchIssueRawResponse)
 	if err != nil {
 		return nil, err
 	}
 
-	if response.Body != nil {
-		defer response.Body.Close()
+	for i := range jiraSearchIssueResponse.Issues {
+		jiraSearchIssueResponse.Issues[i].Fields.Extra = jiraSearchIssueRawResponse.Issues[i].Fields
 	}
 
-	body, err := ioutil.ReadAll(response.Body)
+	return jiraSearchIssueResponse, nil
+}
+
+func (s *WorklogService) getJiraResponse(url string, jiraResponse interface{}) error {
+	body, err := s.getJiraBody(url)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, jiraResponse)
+}
+
+func (s *WorklogService) getJiraBody(url string) ([]byte, error) {
+	client := http.Client{Timeout: time.Second * 60}
+
+	request, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	jiraSearchIssueResponse := &models.JiraSearchIssueResponse{}
-	err = json.Unmarshal(body, jiraSearchIssueResponse)
+	encodedToken := base64.URLEncoding.EncodeToString([]byte(s.jiraUser + ":" + s.jiraToken))
+	request.Header.Set("Authorization", "Basic "+encodedToken)
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := client.Do(request)
 	if err != nil {
 		return nil, err
 	}
 
-	return jiraSearchIssueResponse, nil
+	if response.Body != nil {
+		defer response.Body.Close()
+	}
+
+	return ioutil.ReadAll(response.Body)
 }
 
 func (s *WorklogService) getEfforts(results []models.TempoResult) ([]models.Effort, error) {
 	dateToEffort := map[string]models.Effort{}
 
 	for _, result := range results {
-		date := result.StartDate
-
+		// time spent drives hours and cost of the report
 		var timeSpentSeconds int
-		if _, ok := dateToEffort[date]; ok {
-			prevEffort := dateToEffort[date]
-			timeSpentSeconds = prevEffort.TimeSpentSeconds + result.TimeSpentSeconds
-		} else {
+		switch s.costBasis {
+		case constants.CostBasisLogged:
 			timeSpentSeconds = result.TimeSpentSeconds
+		case constants.CostBasisBillable:
+			timeSpentSeconds = result.BillableSeconds
+		default:
+			return nil, errors.New("unknown cost basis: " + s.costBasis)
 		}
 
-		dateToEffort[date] = models.Effort{
-			Date:             result.StartDate,
+		effort := dateToEffort[result.StartDate]
+		effort.Date = result.StartDate
+		effort.TimeSpentSeconds += timeSpentSeconds
+		effort.UnroundedSeconds += timeSpentSeconds
+		effort.WorklogsSeconds = append(effort.WorklogsSeconds, timeSpentSeconds)
+		effort.LoggedSeconds += result.TimeSpentSeconds
+		effort.BillableSeconds += result.BillableSeconds
+		effort.Entries = append(effort.Entries, models.WorklogEntry{
+			StartTime:        result.StartTime,
 			TimeSpentSeconds: timeSpentSeconds,
-		}
+			Description:      result.Description,
+		})
+
+		dateToEffort[result.StartDate] = effort
 	}
 
 	var efforts []models.Effort
@@ -321,5 +1155,9 @@ func (s *WorklogService) getEfforts(results []models.TempoResult) ([]models.Effo
 		efforts = append(efforts, effort)
 	}
 
+	sort.Slice(efforts, func(i, j int) bool {
+		return efforts[i].Date < efforts[j].Date
+	})
+
 	return efforts, nil
 }
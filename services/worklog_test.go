@@ -0,0 +1,557 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"tempo-worklog/models"
+	"testing"
+)
+
+// newTestWorklogService returns the service with Jira and Tempo urls pointing to the test server,
+// the server responds with the body of the request path.
+func newTestWorklogService(t *testing.T, pathToBody map[string]string, issueFields ...string) *WorklogService {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := pathToBody[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	s := NewWorklogService(server.URL, "user", "token", "token", false, "logged", issueFields, "", models.AttributesAppConfig{}, nil, NewProjectConfigService(""))
+	s.tempoTeamsUrl = server.URL + "/teams"
+	s.tempoTeamMembersUrl = server.URL + "/teams/%d/members"
+	s.tempoPlanUrlTemplate = server.URL + "/plans?from=%s&to=%s&offset=%d&limit=%d"
+
+	return s
+}
+
+func TestGetAccountIdToTeam(t *testing.T) {
+	s := newTestWorklogService(t, map[string]string{
+		"/teams":           `{"results": [{"id": 1, "name": "Beta"}, {"id": 2, "name": "alpha"}]}`,
+		"/teams/1/members": `{"results": [{"member": {"accountId": "a"}}, {"member": {"accountId": "b"}}]}`,
+		"/teams/2/members": `{"results": [{"member": {"accountId": "a"}}]}`,
+	})
+
+	accountIdToTeam, err := s.getAccountIdToTeam()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// members of several teams get the first team by name
+	want := map[string]string{"a": "alpha", "b": "Beta"}
+	if !reflect.DeepEqual(accountIdToTeam, want) {
+		t.Errorf("getAccountIdToTeam() = %v, want %v", accountIdToTeam, want)
+	}
+}
+
+func TestGetUsersTeam(t *testing.T) {
+	s := newTestWorklogService(t, nil)
+	issueKeyToFields := map[string]models.JiraSearchIssueFields{"PRJ-1": {Summary: "Task"}}
+
+	tests := []struct {
+		name            string
+		configTeam      string
+		accountIdToTeam map[string]string
+		want            string
+	}{
+		{"no team", "", nil, ""},
+		{"tempo team", "", map[string]string{"a": "Tempo"}, "Tempo"},
+		{"project config team goes first", "Config", map[string]string{"a": "Tempo"}, "Config"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			results := []models.TempoResult{{
+				Author:           models.TempoAuthor{AccountId: "a", DisplayName: "John Smith"},
+				Issue:            models.TempoIssue{Key: "PRJ-1"},
+				StartDate:        "2023-01-02",
+				TimeSpentSeconds: 3600,
+			}}
+			projectConfig := &models.ProjectConfig{UserNameToConfig: map[string]models.UserConfig{
+				"John Smith": {Team: test.configTeam},
+			}}
+
+			users, err := s.getUsers(results, projectConfig, test.accountIdToTeam, issueKeyToFields, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(users) != 1 || users[0].Team != test.want {
+				t.Errorf("getUsers() = %+v, want team %q", users, test.want)
+			}
+		})
+	}
+}
+
+func TestGetEpic(t *testing.T) {
+	epic := &models.JiraParentIssue{Key: "PRJ-10"}
+	story := &models.JiraParentIssue{Key: "PRJ-2"}
+	parentKeyToFields := map[string]models.JiraSearchIssueFields{"PRJ-2": {Parent: epic}}
+	epicType := models.JiraIssueType{Name: "Epic", HierarchyLevel: 1}
+
+	tests := []struct {
+		name   string
+		fields models.JiraSearchIssueFields
+		want   *models.JiraParentIssue
+	}{
+		{"no parent", models.JiraSearchIssueFields{}, nil},
+		{"story of epic", models.JiraSearchIssueFields{Parent: epic}, epic},
+		{"sub-task of story", models.JiraSearchIssueFields{IssueType: models.JiraIssueType{Subtask: true}, Parent: story}, epic},
+		{"sub-task of story without epic", models.JiraSearchIssueFields{IssueType: models.JiraIssueType{Subtask: true}, Parent: &models.JiraParentIssue{Key: "PRJ-3"}}, nil},
+		{"epic", models.JiraSearchIssueFields{Summary: "Epic", IssueType: epicType}, &models.JiraParentIssue{Key: "PRJ-1", Fields: models.JiraParentIssueFields{Summary: "Epic", IssueType: epicType}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := newTestWorklogService(t, nil).getEpic("PRJ-1", test.fields, parentKeyToFields)
+
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("getEpic() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGetFieldText(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{"null", nil, ""},
+		{"text", "Sprint 1", "Sprint 1"},
+		{"bool", true, "true"},
+		{"number", 2.5, "2.5"},
+		{"status", map[string]interface{}{"name": "Done", "id": "1"}, "Done"},
+		{"select option", map[string]interface{}{"value": "High"}, "High"},
+		{"user", map[string]interface{}{"displayName": "John Smith", "accountId": "a"}, "John Smith"},
+		{"unknown object", map[string]interface{}{"id": "1"}, ""},
+		{"labels", []interface{}{"backend", "", "api"}, "backend, api"},
+		{"components", []interface{}{map[string]interface{}{"name": "Core"}, map[string]interface{}{"name": "UI"}}, "Core, UI"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := newTestWorklogService(t, nil).getFieldText(test.value)
+
+			if got != test.want {
+				t.Errorf("getFieldText() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGetIssuesFields(t *testing.T) {
+	s := newTestWorklogService(t, map[string]string{
+		"/rest/api/2/search": `{"total": 1, "issues": [{"key": "PRJ-1", "fields": {
+			"summary": "Task", "issuetype": {"name": "Story"}, "status": {"name": "Done"}, "labels": ["api"]}}]}`,
+	}, "status", "labels", "customfield_10001")
+
+	results := []models.TempoResult{{Issue: models.TempoIssue{Key: "PRJ-1"}, StartDate: "2023-01-02", TimeSpentSeconds: 3600}}
+
+	issueKeyToFields, parentKeyToFields, err := s.getResultsIssueFields(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := s.getIssues(results, issueKeyToFields, parentKeyToFields)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantFields := map[string]string{"status": "Done", "labels": "api", "customfield_10001": ""}
+	if len(issues) != 1 || issues[0].Summary != "Task" || issues[0].Type != "Story" || !reflect.DeepEqual(issues[0].Fields, wantFields) {
+		t.Errorf("getIssues() = %+v, want PRJ-1 Task Story with fields %v", issues, wantFields)
+	}
+}
+
+func TestFilterTempoResults(t *testing.T) {
+	var gotJql string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotJql = r.URL.Query().Get("jql")
+		w.Write([]byte(`{"total": 1, "issues": [{"key": "PRJ-2"}]}`))
+	}))
+	defer server.Close()
+
+	results := []models.TempoResult{{Issue: models.TempoIssue{Key: "PRJ-1"}}, {Issue: models.TempoIssue{Key: "PRJ-2"}}}
+
+	tests := []struct {
+		name       string
+		jql        string
+		projectJql string
+		wantJql    string
+		wantKeys   []string
+	}{
+		{"no filters", "", " ", "", []string{"PRJ-1", "PRJ-2"}},
+		{"input args", "labels = client", "", `project = "PRJ" AND (labels = client)`, []string{"PRJ-2"}},
+		{"project config", "", "labels != internal", `project = "PRJ" AND (labels != internal)`, []string{"PRJ-2"}},
+		{"both", "a = 1 OR b = 2", "c = 3", `project = "PRJ" AND (a = 1 OR b = 2) AND (c = 3)`, []string{"PRJ-2"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gotJql = ""
+			projectConfigs := map[string]models.ProjectAppConfig{"prj": {Jql: test.projectJql}}
+			s := NewWorklogService(server.URL, "user", "token", "token", false, "logged", nil, "", models.AttributesAppConfig{}, projectConfigs, NewProjectConfigService(""))
+
+			filteredResults, err := s.filterTempoResults("PRJ", test.jql, results)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if gotJql != test.wantJql {
+				t.Errorf("searched jql is %q, want %q", gotJql, test.wantJql)
+			}
+
+			var keys []string
+			for _, result := range filteredResults {
+				keys = append(keys, result.Issue.Key)
+			}
+			if !reflect.DeepEqual(keys, test.wantKeys) {
+				t.Errorf("filterTempoResults() = %v, want %v", keys, test.wantKeys)
+			}
+		})
+	}
+}
+
+func TestGetAccountIds(t *testing.T) {
+	s := newTestWorklogService(t, map[string]string{
+		"/teams":           `{"results": [{"id": 1, "name": "Backend"}]}`,
+		"/teams/1/members": `{"results": [{"member": {"accountId": "a"}}, {"member": {"accountId": "b"}}]}`,
+	})
+
+	tests := []struct {
+		name     string
+		userKeys []string
+		want     []string
+		wantErr  string
+	}{
+		{name: "account ids", userKeys: []string{"b", "c"}, want: []string{"b", "c"}},
+		{name: "team members without duplicates", userKeys: []string{"b", "team:backend", "c"}, want: []string{"b", "a", "c"}},
+		{name: "unknown team", userKeys: []string{"team:Frontend"}, wantErr: "unknown tempo team: Frontend"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			accountIds, err := s.getAccountIds(test.userKeys)
+			if len(test.wantErr) > 0 {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("getAccountIds() error = %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(accountIds, test.want) {
+				t.Errorf("getAccountIds() = %v, want %v", accountIds, test.want)
+			}
+		})
+	}
+}
+
+func TestGetProjectKey(t *testing.T) {
+	tests := []struct {
+		issueKey string
+		want     string
+	}{
+		{"PRJ-123", "PRJ"},
+		{"MY-PRJ-1", "MY-PRJ"},
+		{"PRJ", "PRJ"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.issueKey, func(t *testing.T) {
+			if got := newTestWorklogService(t, nil).getProjectKey(test.issueKey); got != test.want {
+				t.Errorf("getProjectKey() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestGetEfforts(t *testing.T) {
+	results := []models.TempoResult{
+		{StartDate: "2023-01-03", StartTime: "09:00:00", TimeSpentSeconds: 3600, BillableSeconds: 1800, Description: "Design"},
+		{StartDate: "2023-01-02", StartTime: "10:00:00", TimeSpentSeconds: 1800, BillableSeconds: 0, Description: "Call"},
+		{StartDate: "2023-01-03", StartTime: "14:00:00", TimeSpentSeconds: 1800, BillableSeconds: 1800, Description: "Review"},
+	}
+
+	tests := []struct {
+		costBasis string
+		want      []models.Effort
+		wantErr   string
+	}{
+		{
+			costBasis: "logged",
+			want: []models.Effort{
+				{Date: "2023-01-02", TimeSpentSeconds: 1800, UnroundedSeconds: 1800, WorklogsSeconds: []int{1800}, LoggedSeconds: 1800,
+					Entries: []models.WorklogEntry{{StartTime: "10:00:00", TimeSpentSeconds: 1800, Description: "Call"}}},
+				{Date: "2023-01-03", TimeSpentSeconds: 5400, UnroundedSeconds: 5400, WorklogsSeconds: []int{3600, 1800}, LoggedSeconds: 5400, BillableSeconds: 3600,
+					Entries: []models.WorklogEntry{
+						{StartTime: "09:00:00", TimeSpentSeconds: 3600, Description: "Design"},
+						{StartTime: "14:00:00", TimeSpentSeconds: 1800, Description: "Review"},
+					}},
+			},
+		},
+		{
+			costBasis: "billable",
+			want: []models.Effort{
+				{Date: "2023-01-02", WorklogsSeconds: []int{0}, LoggedSeconds: 1800,
+					Entries: []models.WorklogEntry{{StartTime: "10:00:00", Description: "Call"}}},
+				{Date: "2023-01-03", TimeSpentSeconds: 3600, UnroundedSeconds: 3600, WorklogsSeconds: []int{1800, 1800}, LoggedSeconds: 5400, BillableSeconds: 3600,
+					Entries: []models.WorklogEntry{
+						{StartTime: "09:00:00", TimeSpentSeconds: 1800, Description: "Design"},
+						{StartTime: "14:00:00", TimeSpentSeconds: 1800, Description: "Review"},
+					}},
+			},
+		},
+		{costBasis: "planned", wantErr: "unknown cost basis: planned"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.costBasis, func(t *testing.T) {
+			s := newTestWorklogService(t, nil)
+			s.costBasis = test.costBasis
+
+			efforts, err := s.getEfforts(results)
+			if len(test.wantErr) > 0 {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("getEfforts() error = %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(efforts, test.want) {
+				t.Errorf("getEfforts() = %+v, want %+v", efforts, test.want)
+			}
+		})
+	}
+}
+
+func newTestTempoResult(issueKey string, attributes ...string) models.TempoResult {
+	result := models.TempoResult{Issue: models.TempoIssue{Key: issueKey}, StartDate: "2023-01-02", TimeSpentSeconds: 3600}
+	for i := 0; i+1 < len(attributes); i += 2 {
+		result.Attributes.Values = append(result.Attributes.Values, models.TempoAttribute{Key: attributes[i], Value: attributes[i+1]})
+	}
+
+	return result
+}
+
+func TestFilterTempoResultsByAttributes(t *testing.T) {
+	results := []models.TempoResult{
+		newTestTempoResult("PRJ-1", "_Account_", "ACME", "_WorkType_", "Development"),
+		newTestTempoResult("PRJ-2", "_Account_", "acme", "_WorkType_", "Meeting"),
+		newTestTempoResult("PRJ-3", "_Account_", "Other"),
+		newTestTempoResult("PRJ-4"),
+	}
+
+	tests := []struct {
+		name   string
+		filter map[string][]string
+		want   []string
+	}{
+		{"no filter", nil, []string{"PRJ-1", "PRJ-2", "PRJ-3", "PRJ-4"}},
+		{"one attribute", map[string][]string{"_account_": {"ACME"}}, []string{"PRJ-1", "PRJ-2"}},
+		{"all attributes must match", map[string][]string{"_account_": {"ACME", "Other"}, "_worktype_": {"development"}}, []string{"PRJ-1"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newTestWorklogService(t, nil)
+			s.attributesConfig.Filter = test.filter
+
+			var keys []string
+			for _, result := range s.filterTempoResultsByAttributes("PRJ", results) {
+				keys = append(keys, result.Issue.Key)
+			}
+
+			if !reflect.DeepEqual(keys, test.want) {
+				t.Errorf("filterTempoResultsByAttributes() = %v, want %v", keys, test.want)
+			}
+		})
+	}
+}
+
+func TestGetWorkTypeRate(t *testing.T) {
+	tests := []struct {
+		name         string
+		result       models.TempoResult
+		wantWorkType string
+		wantRate     int
+	}{
+		{"no work type", newTestTempoResult("PRJ-1"), "", 0},
+		{"work type without rate", newTestTempoResult("PRJ-1", "_WorkType_", "Meeting"), "", 0},
+		{"work type with rate", newTestTempoResult("PRJ-1", "_worktype_", "Consulting"), "Consulting", 50},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newTestWorklogService(t, nil)
+			s.attributesConfig = models.AttributesAppConfig{WorkTypeKey: "_WorkType_", WorkTypeRates: map[string]int{"consulting": 50}}
+
+			workType, rate := s.getWorkTypeRate(test.result)
+			if workType != test.wantWorkType || rate != test.wantRate {
+				t.Errorf("getWorkTypeRate() = %q, %d, want %q, %d", workType, rate, test.wantWorkType, test.wantRate)
+			}
+		})
+	}
+}
+
+func TestGetUserApprovals(t *testing.T) {
+	fromToBody := map[string]string{
+		"2023-01-04": `{"period": {"from": "2023-01-02", "to": "2023-01-08"}, "status": {"key": "APPROVED"}}`,
+		"2023-01-09": `{"period": {"from": "2023-01-09", "to": "2023-01-15"}, "status": {"key": "OPEN"}}`,
+		"2023-01-16": `{}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(fromToBody[r.URL.Query().Get("from")]))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		dateTo  string
+		wantTo  []string
+		wantErr string
+	}{
+		{name: "periods within dates", dateTo: "2023-01-10", wantTo: []string{"2023-01-08", "2023-01-15"}},
+		{name: "no period", dateTo: "2023-01-16", wantErr: "no timesheet approval period of user 1 for 2023-01-16"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newTestWorklogService(t, nil)
+			s.tempoApprovalUrlTemplate = server.URL + "/timesheet-approvals/user/%s?from=%s&to=%s"
+
+			approvals, err := s.getUserApprovals("1", "2023-01-04", test.dateTo)
+			if len(test.wantErr) > 0 {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("getUserApprovals() error = %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var periodsTo []string
+			for _, approval := range approvals {
+				periodsTo = append(periodsTo, approval.Period.To)
+			}
+			if !reflect.DeepEqual(periodsTo, test.wantTo) {
+				t.Errorf("getUserApprovals() periods end %v, want %v", periodsTo, test.wantTo)
+			}
+		})
+	}
+}
+
+func TestGetPlans(t *testing.T) {
+	s := newTestWorklogService(t, map[string]string{
+		"/plans": `{"metadata": {"count": 5, "offset": 0, "limit": 100}, "results": [
+			{"totalPlannedSecondsInScope": 3600, "planItem": {"id": 1, "type": "ISSUE"}, "assignee": {"accountId": "1", "type": "USER"}},
+			{"totalPlannedSecondsInScope": 1800, "planItem": {"id": 10000, "type": "PROJECT"}, "assignee": {"accountId": "2", "type": "USER"}},
+			{"totalPlannedSecondsInScope": 7200, "planItem": {"id": 20000, "type": "PROJECT"}, "assignee": {"accountId": "1", "type": "USER"}},
+			{"totalPlannedSecondsInScope": 7200, "planItem": {"id": 2, "type": "ISSUE"}, "assignee": {"accountId": "3", "type": "USER"}},
+			{"totalPlannedSecondsInScope": 7200, "planItem": {"id": 1, "type": "ISSUE"}, "assignee": {"type": "GENERIC"}}
+		]}`,
+		"/rest/api/2/project/PRJ": `{"id": "10000", "key": "PRJ"}`,
+		"/rest/api/2/search":      `{"total": 1, "startAt": 0, "maxResults": 100, "issues": [{"id": "1", "key": "PRJ-1"}]}`,
+		"/rest/api/2/user":        `{"accountId": "2", "displayName": "Jane Doe"}`,
+	})
+
+	worklog := newTestWorklog("PRJ")
+	err := s.GetPlans(worklog, nil, "2023-01-02", "2023-01-08")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []models.Plan{
+		{AccountId: "1", DisplayName: "John Smith", PlannedSeconds: 3600},
+		{AccountId: "2", DisplayName: "Jane Doe", PlannedSeconds: 1800},
+	}
+	if !reflect.DeepEqual(worklog.Projects[0].Plans, want) {
+		t.Errorf("GetPlans() put plans %+v, want %+v", worklog.Projects[0].Plans, want)
+	}
+}
+
+func TestGetPlanPages(t *testing.T) {
+	offsetToBody := map[string]string{
+		"0":   `{"metadata": {"count": 100, "offset": 0, "limit": 100}, "results": [{"planItem": {"id": 1, "type": "ISSUE"}, "assignee": {"type": "USER"}}]}`,
+		"100": `{"metadata": {"count": 1, "offset": 100, "limit": 100}, "results": [{"planItem": {"id": 2, "type": "ISSUE"}, "assignee": {"type": "USER"}}]}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(offsetToBody[r.URL.Query().Get("offset")]))
+	}))
+	defer server.Close()
+
+	plans, err := newTestWorklogService(t, nil).getPlanPages(server.URL+"/plans/user/%s?from=%s&to=%s&offset=%d&limit=%d", "1", "2023-01-02", "2023-01-08")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, plan := range plans {
+		ids = append(ids, plan.PlanItem.Id.String())
+	}
+	if want := []string{"1", "2"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("getPlanPages() plan items are %v, want %v", ids, want)
+	}
+}
+
+func TestGetPlanItemProjectKeys(t *testing.T) {
+	var projectRequests, searchJqls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/rest/api/2/project/") {
+			projectRequests = append(projectRequests, r.URL.Path)
+			w.Write([]byte(`{"id": "10000", "key": "PRJ"}`))
+			return
+		}
+
+		searchJqls = append(searchJqls, r.URL.Query().Get("jql"))
+		w.Write([]byte(`{"total": 1, "startAt": 0, "maxResults": 100, "issues": [{"id": "1", "key": "PRJ-1"}]}`))
+	}))
+	defer server.Close()
+
+	var plans []models.TempoPlan
+	for i := 1; i <= 150; i++ {
+		plans = append(plans, models.TempoPlan{PlanItem: models.TempoPlanItem{Id: json.Number(strconv.Itoa(i)), Type: "ISSUE"}})
+	}
+	plans = append(plans, models.TempoPlan{PlanItem: models.TempoPlanItem{Id: "10000", Type: "PROJECT"}})
+
+	s := NewWorklogService(server.URL, "user", "token", "token", false, "logged", nil, "", models.AttributesAppConfig{}, nil, NewProjectConfigService(""))
+	for i := 0; i < 2; i++ {
+		planItemToProjectKey, err := s.getPlanItemProjectKeys(plans, []string{"PRJ"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := map[string]string{"ISSUE:1": "PRJ", "PROJECT:10000": "PRJ"}
+		if !reflect.DeepEqual(planItemToProjectKey, want) {
+			t.Errorf("getPlanItemProjectKeys() = %v, want %v", planItemToProjectKey, want)
+		}
+	}
+
+	if len(projectRequests) != 1 {
+		t.Errorf("project is requested %d times, want once", len(projectRequests))
+	}
+
+	// 150 issues are searched by two chunks of every call
+	if len(searchJqls) != 4 || !strings.HasPrefix(searchJqls[0], "project in (PRJ) AND id in (1,2,") || !strings.HasSuffix(searchJqls[1], ",150)") {
+		t.Errorf("issues are searched by %q, want chunks of 100 ids within the project", searchJqls)
+	}
+}